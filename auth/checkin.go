@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// checkInAudience is audience of check-in token, so it can't be used as
+// access token and the other way around.
+const checkInAudience = "check-in"
+
+// CheckInClaims is claims of signed check-in link of a meeting.
+type CheckInClaims struct {
+	MeetingID int `json:"meeting_id"`
+	jwt.RegisteredClaims
+}
+
+// GetCheckInToken is function to sign check-in token of meeting, it's put in
+// check-in link of QR code.
+func GetCheckInToken(meetingID int, ttl time.Duration) (string, time.Time, error) {
+	keys := keySet.Load()
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := CheckInClaims{
+		MeetingID: meetingID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    keys.issuer,
+			Audience:  jwt.ClaimStrings{checkInAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := keys.sign(claims)
+	return token, expiresAt, err
+}
+
+// VerifyCheckInToken is function to verify check-in token and return id of
+// the meeting.
+func VerifyCheckInToken(token string) (int, error) {
+	var claims CheckInClaims
+	keys := keySet.Load()
+	_, err := jwt.ParseWithClaims(token, &claims, keys.keyFunc, jwt.WithIssuer(keys.issuer),
+		jwt.WithAudience(checkInAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, err
+	}
+	if claims.MeetingID == 0 {
+		return 0, errors.New("check-in token doesn't have meeting")
+	}
+	return claims.MeetingID, nil
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// CodePeriod is how long a rotating code is shown before the next code.
+const CodePeriod = 30 * time.Second
+
+// RotatingCode is function to generate 6 digits code that change every
+// CodePeriod, it's TOTP (RFC 6238) with the given secret.
+func RotatingCode(secret string, t time.Time) string {
+	return codeAt(secret, t.Unix()/int64(CodePeriod/time.Second))
+}
+
+// VerifyRotatingCode is function to check code at the given time, the code
+// of the previous period is accepted too because it's typed by hand.
+func VerifyRotatingCode(secret, code string, t time.Time) bool {
+	counter := t.Unix() / int64(CodePeriod/time.Second)
+	for _, c := range []int64{counter, counter - 1} {
+		if hmac.Equal([]byte(codeAt(secret, c)), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CodeExpiresAt return the time when code at t is changed.
+func CodeExpiresAt(t time.Time) time.Time {
+	return t.Truncate(CodePeriod).Add(CodePeriod)
+}
+
+func codeAt(secret string, counter int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
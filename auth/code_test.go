@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// test vectors of RFC 6238 with SHA1, the last 6 digits of 8 digits code.
+func TestRotatingCode(t *testing.T) {
+	secret := "12345678901234567890"
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		if got := RotatingCode(secret, time.Unix(tt.unix, 0)); got != tt.want {
+			t.Errorf("RotatingCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyRotatingCode(t *testing.T) {
+	secret := "meeting-secret"
+	// start of a period.
+	shown := time.Unix(1790000010, 0).Truncate(CodePeriod)
+	code := RotatingCode(secret, shown)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		at     time.Time
+		want   bool
+	}{
+		{"same time", secret, code, shown, true},
+		{"end of current period", secret, code, shown.Add(CodePeriod - time.Second), true},
+		{"previous period", secret, code, shown.Add(CodePeriod), true},
+		{"end of previous period", secret, code, shown.Add(2*CodePeriod - time.Second), true},
+		{"two periods later", secret, code, shown.Add(2 * CodePeriod), false},
+		{"before shown", secret, code, shown.Add(-time.Second), false},
+		{"other secret", "other-secret", code, shown, false},
+		{"wrong code", secret, "000000", shown, code == "000000"},
+		{"empty code", secret, "", shown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyRotatingCode(tt.secret, tt.code, tt.at); got != tt.want {
+				t.Errorf("VerifyRotatingCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeExpiresAt(t *testing.T) {
+	start := time.Unix(1790000010, 0).Truncate(CodePeriod)
+	tests := []struct {
+		at   time.Time
+		want time.Time
+	}{
+		{start, start.Add(CodePeriod)},
+		{start.Add(CodePeriod - time.Nanosecond), start.Add(CodePeriod)},
+		{start.Add(CodePeriod), start.Add(2 * CodePeriod)},
+	}
+	for _, tt := range tests {
+		if got := CodeExpiresAt(tt.at); !got.Equal(tt.want) {
+			t.Errorf("CodeExpiresAt(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
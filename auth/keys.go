@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is key to sign or verify token. Key without private key is only used
+// to verify, e.g. retired key after rotation.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// KeySet is all key to sign and verify token, with issuer and lifetime of
+// the token. It's swapped as a whole when configuration is reloaded.
+type KeySet struct {
+	// secret is HS256 key, it's nil when hmac is disabled.
+	secret  []byte
+	signing *Key
+	keys    map[string]*Key
+	issuer  string
+	ttl     time.Duration
+}
+
+// LoadKeySet is function to load keys from configuration. Every *.pem file
+// in keys directory is a key and its file name is the key id (kid). RSA key
+// sign with RS256 and Ed25519 key sign with EdDSA, when keys directory is
+// empty token is signed with HS256 secret.
+func LoadKeySet(cfg config.JWT) (*KeySet, error) {
+	keySet := &KeySet{
+		keys:   make(map[string]*Key),
+		issuer: cfg.Issuer,
+		ttl:    cfg.TTL.Duration,
+	}
+	if cfg.Secret != "" {
+		keySet.secret = []byte(cfg.Secret)
+	}
+
+	if cfg.KeysDir == "" {
+		if keySet.secret == nil {
+			return nil, errors.New("auth: jwt secret or keys directory is required")
+		}
+		return keySet, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(cfg.KeysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var privateKeys []*Key
+	for _, file := range files {
+		key, err := loadKey(file)
+		if err != nil {
+			return nil, err
+		}
+		keySet.keys[key.ID] = key
+		if key.Private != nil {
+			privateKeys = append(privateKeys, key)
+		}
+	}
+
+	switch {
+	case cfg.SigningKeyID != "":
+		key, ok := keySet.keys[cfg.SigningKeyID]
+		if !ok || key.Private == nil {
+			return nil, fmt.Errorf("auth: private key %q is not found in %s", cfg.SigningKeyID, cfg.KeysDir)
+		}
+		keySet.signing = key
+	case len(privateKeys) == 1:
+		keySet.signing = privateKeys[0]
+	case len(privateKeys) == 0:
+		return nil, fmt.Errorf("auth: no private key in %s", cfg.KeysDir)
+	default:
+		return nil, fmt.Errorf("auth: %s has more than one private key, set jwt signing key id", cfg.KeysDir)
+	}
+	return keySet, nil
+}
+
+func loadKey(file string) (*Key, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("auth: %s is not pem file", file)
+	}
+
+	key := &Key{
+		ID: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("auth: %s has unsupported pem type %q", file, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auth: parse %s: %w", file, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("auth: %s is not RSA or Ed25519 key", file)
+	}
+	return key, nil
+}
+
+// sign is function to sign claims with signing key.
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	if k.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+
+	jwtToken := jwt.NewWithClaims(k.signing.Method, claims)
+	jwtToken.Header["kid"] = k.signing.ID
+	return jwtToken.SignedString(k.signing.Private)
+}
+
+// keyFunc return verification key by algorithm and kid of token, key of
+// other algorithm is never returned.
+func (k *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+		if k.secret == nil {
+			return nil, errors.New("hmac token is not accepted")
+		}
+		return k.secret, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if key.Method.Alg() != t.Method.Alg() {
+		return nil, fmt.Errorf("key %q is not %s key", kid, t.Method.Alg())
+	}
+	return key.Public, nil
+}
+
+// JWK is json web key of public key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is set of json web key, see RFC 7517.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS return public keys to verify token, HS256 secret is never published.
+func (k *KeySet) JWKS() JWKS {
+	jwks := JWKS{
+		Keys: []JWK{},
+	}
+
+	var ids []string
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	encode := base64.RawURLEncoding.EncodeToString
+	for _, id := range ids {
+		key := k.keys[id]
+		jwk := JWK{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encode(public.N.Bytes())
+			jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = encode(public)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
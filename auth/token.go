@@ -2,9 +2,12 @@ package auth
 
 import (
 	"errors"
+	"sync/atomic"
 	"time"
 
+	"github.com/Aeroxee/kafekoding-api/config"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type UserAuth struct{}
@@ -12,6 +15,12 @@ type UserAuth struct{}
 type Credential struct {
 	UserID   int    `json:"user_id"`
 	Username string `json:"username"`
+	// TokenVersion must equal to token version of user, user's token
+	// version is increased to revoke all token of the user.
+	TokenVersion int `json:"token_version"`
+	// SessionID is id of login session, token is rejected when the
+	// session is revoked.
+	SessionID string `json:"sid,omitempty"`
 }
 
 type Claims struct {
@@ -19,27 +28,55 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-var secretKey = []byte("secret key")
+var keySet atomic.Pointer[KeySet]
+
+func init() {
+	cfg := config.Default().JWT
+	keySet.Store(&KeySet{
+		secret: []byte(cfg.Secret),
+		issuer: cfg.Issuer,
+		ttl:    cfg.TTL.Duration,
+	})
+}
+
+// Configure is function to load signing keys and set lifetime of token. It
+// can be called again to reload keys after rotation.
+func Configure(cfg config.JWT) error {
+	newKeySet, err := LoadKeySet(cfg)
+	if err != nil {
+		return err
+	}
+
+	keySet.Store(newKeySet)
+	return nil
+}
+
+// GetJWKS return public keys to verify token.
+func GetJWKS() JWKS {
+	return keySet.Load().JWKS()
+}
 
 func GetToken(credential Credential) (string, error) {
-	expirationTime := time.Now().Add(time.Hour * 24)
+	keys := keySet.Load()
+	expirationTime := time.Now().Add(keys.ttl)
 	claims := Claims{
 		Credential: credential,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// ID is used to revoke this token on logout.
+			ID:        uuid.NewString(),
+			Issuer:    keys.issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, err := jwtToken.SignedString(secretKey)
-	return token, err
+	return keys.sign(claims)
 }
 
 func VerifyToken(token string) (Claims, error) {
 	var claims Claims
-	jwtToken, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
+	keys := keySet.Load()
+	jwtToken, err := jwt.ParseWithClaims(token, &claims, keys.keyFunc, jwt.WithIssuer(keys.issuer))
 	if err != nil {
 		return claims, err
 	}
@@ -47,5 +84,9 @@ func VerifyToken(token string) (Claims, error) {
 	if !jwtToken.Valid {
 		return claims, errors.New("token anda sudah kadaluarsa")
 	}
+	// token with audience, e.g. check-in link, is not access token.
+	if len(claims.Audience) > 0 {
+		return claims, errors.New("token is not access token")
+	}
 	return claims, nil
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"gorm.io/gorm"
+)
+
+// createAdmin is command to create first ADMIN user without manual sql.
+func createAdmin(args []string) error {
+	flags := flag.NewFlagSet("createadmin", flag.ExitOnError)
+	configPath := configFlag(flags)
+	username := flags.String("username", "", "username of admin (required)")
+	email := flags.String("email", "", "email of admin (required)")
+	firstName := flags.String("first-name", "Admin", "first name of admin")
+	lastName := flags.String("last-name", "KafeKoding", "last name of admin")
+	password := flags.String("password", "", "password of admin, read from stdin when empty")
+	flags.Parse(args)
+
+	if *username == "" || *email == "" {
+		flags.Usage()
+		return errors.New("createadmin: -username and -email is required")
+	}
+
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("createadmin: read password: %w", err)
+		}
+		*password = strings.TrimSpace(line)
+	}
+	if *password == "" {
+		return errors.New("createadmin: password is required")
+	}
+
+	_, db, err := openDatabase(*configPath)
+	if err != nil {
+		return err
+	}
+	defer models.Close(db)
+
+	user := models.User{
+		FirstName: *firstName,
+		LastName:  *lastName,
+		Username:  *username,
+		Email:     *email,
+		Password:  *password,
+		IsActive:  true,
+		Type:      models.ADMIN,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := models.NewUserModel(tx).CreateNewUser(&user); err != nil {
+			return err
+		}
+		// ADMIN is zero value, it's replaced by column default on create.
+		return tx.Model(&user).Update("type", models.ADMIN).Error
+	})
+	if err != nil {
+		return fmt.Errorf("createadmin: %w", err)
+	}
+
+	fmt.Printf("admin %s is created with id %d\n", user.Username, user.ID)
+	return nil
+}
+
+// promote is command to change type of user, default to ADMIN.
+func promote(args []string) error {
+	flags := flag.NewFlagSet("promote", flag.ExitOnError)
+	configPath := configFlag(flags)
+	typeName := flags.String("type", models.ADMIN.String(), "new type of user: ADMIN, MENTOR, EDITOR or MEMBER")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kafekoding-api promote [flags] <username>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	userType, err := models.ParseUserType(*typeName)
+	if err != nil {
+		return err
+	}
+
+	_, db, err := openDatabase(*configPath)
+	if err != nil {
+		return err
+	}
+	defer models.Close(db)
+
+	user, err := models.NewUserModel(db).GetUserByUsername(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("promote: user %s: %w", flags.Arg(0), err)
+	}
+
+	err = db.Model(&user).Update("type", userType).Error
+	if err != nil {
+		return fmt.Errorf("promote: %w", err)
+	}
+
+	fmt.Printf("user %s is now %s\n", user.Username, userType)
+	return nil
+}
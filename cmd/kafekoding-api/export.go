@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"gorm.io/gorm"
+)
+
+// exporters is list of data that can be exported, password is never
+// exported because it's hidden from json.
+var exporters = map[string]func(db *gorm.DB) (any, error){
+	"users": func(db *gorm.DB) (any, error) {
+		var users []models.User
+		err := db.Order("id").Find(&users).Error
+		return users, err
+	},
+	"classes": func(db *gorm.DB) (any, error) {
+		var classes []models.Class
+		err := db.Order("id").Preload("Mentors").Preload("Members").Preload("Images").
+			Preload("Meetings").Find(&classes).Error
+		return classes, err
+	},
+	"articles": func(db *gorm.DB) (any, error) {
+		var articles []models.Article
+		err := db.Order("id").Preload("Comments").Find(&articles).Error
+		return articles, err
+	},
+}
+
+// export is command to export data as json.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	configPath := configFlag(flags)
+	output := flags.String("output", "", "output file, default is stdout")
+	tables := flags.String("tables", "users,classes,articles", "comma separated data to export")
+	flags.Parse(args)
+
+	_, db, err := openDatabase(*configPath)
+	if err != nil {
+		return err
+	}
+	defer models.Close(db)
+
+	result := map[string]any{
+		"exported_at": time.Now(),
+	}
+	for _, name := range strings.Split(*tables, ",") {
+		name = strings.TrimSpace(name)
+		exporter, ok := exporters[name]
+		if !ok {
+			return fmt.Errorf("export: unknown table %q", name)
+		}
+
+		data, err := exporter(db)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", name, err)
+		}
+		result[name] = data
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// genKey is command to generate private key to sign jwt.
+func genKey(args []string) error {
+	flags := flag.NewFlagSet("genkey", flag.ExitOnError)
+	alg := flags.String("alg", "EdDSA", "algorithm of key, EdDSA or RS256")
+	dir := flags.String("dir", "keys", "directory of keys")
+	kid := flags.String("kid", time.Now().Format("20060102"), "key id, it's the file name")
+	flags.Parse(args)
+
+	var private crypto.PrivateKey
+	var err error
+	switch *alg {
+	case "EdDSA":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case "RS256":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		return fmt.Errorf("genkey: algorithm %q is not EdDSA or RS256", *alg)
+	}
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(*dir, *kid+".pem")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s key is written to %s, its key id is %q\n", *alg, path, *kid)
+	return nil
+}
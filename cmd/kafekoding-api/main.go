@@ -1,118 +1,82 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"net/http"
-	"path/filepath"
+	"log"
+	"os"
+	// time zone of meeting schedule doesn't depend on tzdata of the host.
+	_ "time/tzdata"
 
-	"github.com/Aeroxee/kafekoding-api/auth"
-	"github.com/Aeroxee/kafekoding-api/controllers"
-	"github.com/Aeroxee/kafekoding-api/handlers"
-	"github.com/Aeroxee/kafekoding-api/middlewares"
+	"github.com/Aeroxee/kafekoding-api/config"
 	"github.com/Aeroxee/kafekoding-api/models"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-func main() {
-	r := gin.Default()
-	r.SetTrustedProxies([]string{"127.0.0.1"})
-	r.Static("/media", "./media")
-
-	config := cors.Config{
-		AllowAllOrigins: true,
-		AllowMethods:    []string{"GET", "POST", "DELETE", "PUT", "OPTIONS", "PATCH"},
-		AllowHeaders:    []string{"Content-Type", "Authorization"},
-	}
-	c := cors.New(config)
-	r.Use(c)
-
-	v1 := r.Group("/v1")
-
-	// register
-	userHandler := handlers.NewUserHandlerV1()
-	v1.POST("/register", userHandler.RegisterHandler)
-	v1.GET("/activate/:activationCode", userHandler.ActivationHandler)
-	v1.POST("/get-token", userHandler.GetTokenHandler)
-
-	userGroup := v1.Group("/user")
-	userGroup.Use(middlewares.Authentication())
-	controllers.UserController(userGroup)
-
-	classGroupV1WithAuth := v1.Group("/classes")
-	classGroupV1WithAuth.Use(middlewares.Authentication())
-	controllers.ClassControllerV1WithAuth(classGroupV1WithAuth)
-
-	classGroupV1NoAuth := v1.Group("/classes")
-	controllers.ClassControllerV1NoAuth(classGroupV1NoAuth)
+// command is subcommand of kafekoding-api.
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
 
-	// article group no auth
-	articleGroupNoAuth := v1.Group("/articles")
-	controllers.ArticleControllerNoAuth(articleGroupNoAuth)
+var commands = []command{
+	{"serve", "run http server (default)", serve},
+	{"migrate", "manage database schema", migrate},
+	{"createadmin", "create an active ADMIN user", createAdmin},
+	{"promote", "change type of a user", promote},
+	{"seed", "fill database with sample data for development", seed},
+	{"export", "export users, classes and articles as json", export},
+	{"genkey", "generate private key to sign jwt", genKey},
+}
 
-	// article group with auth
-	articleGroupWithAuth := v1.Group("/articles")
-	articleGroupWithAuth.Use(middlewares.Authentication())
-	controllers.ArticleControllerWithAuth(articleGroupWithAuth)
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: kafekoding-api <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.description)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'kafekoding-api <command> -h' for flags of a command.")
+}
 
-	// upload handler
-	r.POST("/upload", func(ctx *gin.Context) {
-		token := ctx.Query("token")
-		if token == "" {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Authentication is required.",
-			})
-			return
-		}
+func main() {
+	log.SetFlags(0)
 
-		claims, err := auth.VerifyToken(token)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
-			return
-		}
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		name, args = args[0], args[1:]
+	}
 
-		_, err = models.GetUserByID(claims.Credential.UserID)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Authentication is required.",
-			})
+	for _, c := range commands {
+		if c.name == name {
+			if err := c.run(args); err != nil {
+				log.Fatal(err)
+			}
 			return
 		}
+	}
 
-		_, h, err := ctx.Request.FormFile("file")
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
-			return
-		}
-		filename := h.Filename
-		filenameUUID := uuid.NewString() + filepath.Ext(filename)
-		destination := fmt.Sprintf("media/upload/%s", filenameUUID)
+	if name != "help" {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
 
-		err = ctx.SaveUploadedFile(h, destination)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
-			return
-		}
+// configFlag add -config flag to command flags.
+func configFlag(flags *flag.FlagSet) *string {
+	return flags.String("config", "", "path to configuration file (yaml or toml)")
+}
 
-		ctx.JSON(http.StatusCreated, gin.H{
-			"status":  "success",
-			"message": "Upload a file successfully",
-			"file":    destination,
-			"ext":     filepath.Ext(filename),
-		})
-	})
+// openDatabase load configuration and open database.
+func openDatabase(configPath string) (config.Config, *gorm.DB, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return cfg, nil, err
+	}
 
-	r.Run(":8000")
+	db, err := models.Open(cfg.Database)
+	return cfg, db, err
 }
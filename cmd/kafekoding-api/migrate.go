@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Aeroxee/kafekoding-api/migrations"
+	"github.com/Aeroxee/kafekoding-api/models"
+)
+
+const migrateUsage = `Usage: kafekoding-api migrate <up|down|status> [flags]
+
+  up      apply pending migrations
+  down    revert applied migrations
+  status  list migrations and when they were applied
+`
+
+// migrate is command to manage database schema.
+func migrate(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	action := args[0]
+	flags := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	configPath := configFlag(flags)
+	to := flags.Int("to", 0, "apply migrations until this version, 0 is latest (up)")
+	steps := flags.Int("steps", 1, "number of migrations to revert (down)")
+	flags.Parse(args[1:])
+
+	_, db, err := openDatabase(*configPath)
+	if err != nil {
+		return err
+	}
+	defer models.Close(db)
+
+	migrator := migrations.New(db)
+	switch action {
+	case "up":
+		applied, err := migrator.Up(*to)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migration")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(*steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no applied migration")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+	return nil
+}
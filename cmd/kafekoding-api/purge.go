@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"gorm.io/gorm"
+)
+
+// purgeJob is background job to delete expired data periodically.
+func purgeJob(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeExpired(db.WithContext(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeExpired(db *gorm.DB) {
+	// expired token is kept for an hour, it's counted by resend rate limit.
+	deleted, err := models.NewUserTokenModel(db).DeleteExpired(time.Now().Add(-time.Hour))
+	if err != nil {
+		log.Printf("purge: user tokens: %v", err)
+	} else if deleted > 0 {
+		log.Printf("purge: deleted %d expired user tokens", deleted)
+	}
+
+	deleted, err = models.NewRefreshTokenModel(db).DeleteExpired(time.Now())
+	if err != nil {
+		log.Printf("purge: refresh tokens: %v", err)
+	} else if deleted > 0 {
+		log.Printf("purge: deleted %d expired refresh tokens", deleted)
+	}
+
+	deleted, err = models.NewRevokedTokenModel(db).DeleteExpired(time.Now())
+	if err != nil {
+		log.Printf("purge: revoked tokens: %v", err)
+	} else if deleted > 0 {
+		log.Printf("purge: deleted %d expired revoked tokens", deleted)
+	}
+
+	deleted, err = models.NewSessionModel(db).DeleteExpired(time.Now())
+	if err != nil {
+		log.Printf("purge: sessions: %v", err)
+	} else if deleted > 0 {
+		log.Printf("purge: deleted %d expired or revoked sessions", deleted)
+	}
+
+	deleted, err = models.NewClassMeetingAttendanceModel(db).DeleteCheckInFailures(time.Now().Add(-time.Hour))
+	if err != nil {
+		log.Printf("purge: check-in failures: %v", err)
+	} else if deleted > 0 {
+		log.Printf("purge: deleted %d old check-in failures", deleted)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gosimple/slug"
+	"gorm.io/gorm"
+)
+
+// seed is command to fill database with sample data, existing data with
+// the same username or slug is kept.
+func seed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	configPath := configFlag(flags)
+	password := flags.String("password", "password", "password of sample users")
+	force := flags.Bool("force", false, "allow seeding outside development")
+	flags.Parse(args)
+
+	cfg, db, err := openDatabase(*configPath)
+	if err != nil {
+		return err
+	}
+	defer models.Close(db)
+
+	if cfg.Env != config.DEVELOPMENT && !*force {
+		return fmt.Errorf("seed: refuse to seed %s database without -force", cfg.Env)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		admin, err := seedUser(tx, "admin", models.ADMIN, *password)
+		if err != nil {
+			return err
+		}
+		mentor, err := seedUser(tx, "mentor", models.MENTOR, *password)
+		if err != nil {
+			return err
+		}
+		member, err := seedUser(tx, "member", models.MEMBER, *password)
+		if err != nil {
+			return err
+		}
+
+		for _, title := range []string{"Golang Dasar", "Web Development"} {
+			class, err := seedClass(tx, title)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&class).Association("Mentors").Append(&mentor); err != nil {
+				return err
+			}
+			if err := tx.Model(&class).Update("owner_id", mentor.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&class).Association("Members").Append(&member); err != nil {
+				return err
+			}
+		}
+
+		article := models.Article{
+			UserID:  admin.ID,
+			Title:   "Selamat Datang di KafeKoding",
+			Slug:    slug.MakeLang("Selamat Datang di KafeKoding", "id"),
+			Content: "Artikel contoh dari seed.",
+			Status:  models.PUBLISHED,
+		}
+		err = tx.Where(models.Article{Slug: article.Slug}).FirstOrCreate(&article).Error
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("seeded users admin, mentor, member with password %q\n", *password)
+		return nil
+	})
+}
+
+func seedUser(tx *gorm.DB, username string, userType models.UserType, password string) (models.User, error) {
+	userModel := models.NewUserModel(tx)
+	user, err := userModel.GetUserByUsername(username)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+
+	user = models.User{
+		FirstName: username,
+		LastName:  "KafeKoding",
+		Username:  username,
+		Email:     username + "@kafekoding.local",
+		Password:  password,
+		IsActive:  true,
+	}
+	if err := userModel.CreateNewUser(&user); err != nil {
+		return user, err
+	}
+	user.Type = userType
+	return user, tx.Model(&user).Update("type", userType).Error
+}
+
+func seedClass(tx *gorm.DB, title string) (models.Class, error) {
+	classSlug := slug.MakeLang(title, "id")
+	class, err := models.NewClassModel(tx).GetClassBySlug(classSlug)
+	if err == nil {
+		return class, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return class, err
+	}
+
+	class = models.Class{
+		Title:       title,
+		Slug:        classSlug,
+		Description: "Kelas contoh dari seed.",
+		IsActive:    true,
+	}
+	if err := models.NewClassModel(tx).CreateNewClass(&class); err != nil {
+		return class, err
+	}
+
+	opened := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Hour)
+	meeting := models.ClassMeeting{
+		ClassID:  class.ID,
+		Title:    "Pertemuan 1",
+		Slug:     "pertemuan-1",
+		Content:  "Perkenalan kelas.",
+		Position: 1,
+		OpenedAt: opened,
+		ClosedAt: opened.Add(2 * time.Hour),
+	}
+	return class, tx.Create(&meeting).Error
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/Aeroxee/kafekoding-api/controllers"
+	"github.com/Aeroxee/kafekoding-api/handlers"
+	"github.com/Aeroxee/kafekoding-api/mailer"
+	"github.com/Aeroxee/kafekoding-api/middlewares"
+	"github.com/Aeroxee/kafekoding-api/migrations"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// serve is command to run http server.
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := configFlag(flags)
+	flags.Parse(args)
+
+	cfg, db, err := openDatabase(*configPath)
+	if err != nil {
+		return err
+	}
+	defer models.Close(db)
+
+	if cfg.Database.AutoMigrate {
+		applied, err := migrations.New(db).Up(0)
+		if err != nil {
+			return err
+		}
+		for _, migration := range applied {
+			log.Printf("migrate: applied %04d_%s", migration.Version, migration.Name)
+		}
+	}
+
+	if err := auth.Configure(cfg.JWT); err != nil {
+		return err
+	}
+	mail := mailer.New(cfg.SMTP)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go purgeJob(ctx, db, cfg.Account.PurgeInterval.Duration)
+	go reloadKeysOnSignal(ctx, *configPath)
+
+	if cfg.Env == config.PRODUCTION {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := gin.Default()
+	r.SetTrustedProxies(cfg.Server.TrustedProxies)
+	r.Static("/media", "./media")
+
+	corsConfig := cors.Config{
+		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "POST", "DELETE", "PUT", "OPTIONS", "PATCH"},
+		AllowHeaders:    []string{"Content-Type", "Authorization"},
+	}
+	c := cors.New(corsConfig)
+	r.Use(c)
+	r.Use(middlewares.Timeout(cfg.Database.QueryTimeout.Duration))
+
+	r.GET("/.well-known/jwks.json", handlers.JWKSHandler)
+
+	v1 := r.Group("/v1")
+
+	userHandler := handlers.NewUserHandlerV1(db, cfg, mail)
+	classHandlerV1 := handlers.NewClassHandlerV1(db, cfg, mail)
+	articleHandlerV1 := handlers.NewArticleHandlerV1(db)
+
+	// register
+	v1.POST("/register", userHandler.RegisterHandler)
+	v1.GET("/activate/:activationCode", userHandler.ActivationHandler)
+	v1.POST("/activation/resend", userHandler.ResendActivationHandler)
+	v1.POST("/get-token", userHandler.GetTokenHandler)
+	v1.POST("/token/refresh", userHandler.RefreshTokenHandler)
+	v1.POST("/password/forgot", userHandler.ForgotPasswordHandler)
+	v1.POST("/password/reset", userHandler.ResetPasswordHandler)
+	v1.GET("/user/calendar/:token", userHandler.CalendarHandler)
+
+	userGroup := v1.Group("/user")
+	userGroup.Use(middlewares.Authentication(db))
+	controllers.UserController(userGroup, userHandler)
+
+	classGroupV1WithAuth := v1.Group("/classes")
+	classGroupV1WithAuth.Use(middlewares.Authentication(db))
+	controllers.ClassControllerV1WithAuth(classGroupV1WithAuth, classHandlerV1)
+
+	classGroupV1NoAuth := v1.Group("/classes")
+	controllers.ClassControllerV1NoAuth(classGroupV1NoAuth, classHandlerV1)
+
+	// article group no auth
+	articleGroupNoAuth := v1.Group("/articles")
+	controllers.ArticleControllerNoAuth(articleGroupNoAuth, articleHandlerV1)
+
+	// article group with auth
+	articleGroupWithAuth := v1.Group("/articles")
+	articleGroupWithAuth.Use(middlewares.Authentication(db))
+	controllers.ArticleControllerWithAuth(articleGroupWithAuth, articleHandlerV1)
+
+	// upload handler
+	r.POST("/upload", func(ctx *gin.Context) {
+		token := ctx.Query("token")
+		if token == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Authentication is required.",
+			})
+			return
+		}
+
+		_, err := middlewares.VerifyToken(db.WithContext(ctx.Request.Context()), token)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		_, h, err := ctx.Request.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		filename := h.Filename
+		filenameUUID := uuid.NewString() + filepath.Ext(filename)
+		destination := fmt.Sprintf("media/upload/%s", filenameUUID)
+
+		err = ctx.SaveUploadedFile(h, destination)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{
+			"status":  "success",
+			"message": "Upload a file successfully",
+			"file":    destination,
+			"ext":     filepath.Ext(filename),
+		})
+	})
+
+	return r.Run(cfg.Server.Addr())
+}
+
+// reloadKeysOnSignal reload jwt keys when SIGHUP is received, so key can be
+// rotated without restarting server.
+func reloadKeysOnSignal(ctx context.Context, configPath string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		}
+
+		cfg, err := config.Load(configPath)
+		if err == nil {
+			err = auth.Configure(cfg.JWT)
+		}
+		if err != nil {
+			log.Printf("reload jwt keys: %v", err)
+			continue
+		}
+		log.Printf("reload jwt keys is successfully")
+	}
+}
@@ -0,0 +1,301 @@
+// This package is a package that functions to load application configuration.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DEVELOPMENT = "development"
+	STAGING     = "staging"
+	PRODUCTION  = "production"
+)
+
+const (
+	MYSQL    = "mysql"
+	POSTGRES = "postgres"
+	SQLITE   = "sqlite"
+)
+
+// defaultDSN is data source name used when it's not configured.
+var defaultDSN = map[string]string{
+	MYSQL:    "root:root@tcp(127.0.0.1:3306)/kafekoding?charset=utf8mb4&parseTime=True&loc=UTC",
+	POSTGRES: "host=127.0.0.1 port=5432 user=postgres password=postgres dbname=kafekoding sslmode=disable TimeZone=UTC",
+	SQLITE:   "kafekoding.db",
+}
+
+// defaultSecretKey is the development secret, it is rejected outside development.
+const defaultSecretKey = "secret key"
+
+// Duration is time.Duration that can be decoded from strings like "24h".
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
+
+// Server is configuration for http server.
+type Server struct {
+	Host           string   `yaml:"host" toml:"host"`
+	Port           int      `yaml:"port" toml:"port"`
+	BaseURL        string   `yaml:"base_url" toml:"base_url"`
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// Addr return address to listen on.
+func (s Server) Addr() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// Database is configuration for database connection.
+type Database struct {
+	// Driver is one of mysql, postgres or sqlite.
+	Driver          string   `yaml:"driver" toml:"driver"`
+	DSN             string   `yaml:"dsn" toml:"dsn"`
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	// QueryTimeout is maximum time for database query in one request.
+	QueryTimeout Duration `yaml:"query_timeout" toml:"query_timeout"`
+	// AutoMigrate apply pending migration when server start.
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate"`
+}
+
+// JWT is configuration for json web token.
+type JWT struct {
+	// Secret is HS256 key, it's used to sign when keys directory is
+	// empty, otherwise it's only used to verify old token and can be
+	// removed when all HS256 token is expired.
+	Secret string `yaml:"secret" toml:"secret"`
+	// KeysDir is directory of RSA or Ed25519 pem keys, file name is the key id.
+	KeysDir string `yaml:"keys_dir" toml:"keys_dir"`
+	// SigningKeyID is id of private key to sign token, it's required when
+	// keys directory has more than one private key.
+	SigningKeyID string `yaml:"signing_key_id" toml:"signing_key_id"`
+	Issuer       string `yaml:"issuer" toml:"issuer"`
+	// TTL is lifetime of access token.
+	TTL Duration `yaml:"ttl" toml:"ttl"`
+	// RefreshTTL is lifetime of refresh token.
+	RefreshTTL Duration `yaml:"refresh_ttl" toml:"refresh_ttl"`
+}
+
+// SMTP is configuration for sending email.
+type SMTP struct {
+	Server   string `yaml:"server" toml:"server"`
+	Port     int    `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	From     string `yaml:"from" toml:"from"`
+}
+
+// Account is configuration for account activation and password reset.
+type Account struct {
+	ActivationTTL    Duration `yaml:"activation_ttl" toml:"activation_ttl"`
+	PasswordResetTTL Duration `yaml:"password_reset_ttl" toml:"password_reset_ttl"`
+	// PasswordResetURL is page to reset password, the token is appended
+	// as query "token". Default is {base_url}/reset-password.
+	PasswordResetURL string `yaml:"password_reset_url" toml:"password_reset_url"`
+	// CheckInURL is page to check in to meeting from QR code, slug of class
+	// and meeting and the token are appended as query "class", "meeting"
+	// and "token". Default is {base_url}/check-in.
+	CheckInURL string `yaml:"check_in_url" toml:"check_in_url"`
+	// ResendCooldown is minimum time between two email of the same kind.
+	ResendCooldown Duration `yaml:"resend_cooldown" toml:"resend_cooldown"`
+	// ResendLimit is maximum email of the same kind for one user in an hour.
+	ResendLimit int `yaml:"resend_limit" toml:"resend_limit"`
+	// PurgeInterval is interval to delete expired token from database.
+	PurgeInterval Duration `yaml:"purge_interval" toml:"purge_interval"`
+}
+
+// Config is struct for all configuration of application.
+type Config struct {
+	Env      string   `yaml:"env" toml:"env"`
+	Server   Server   `yaml:"server" toml:"server"`
+	Database Database `yaml:"database" toml:"database"`
+	JWT      JWT      `yaml:"jwt" toml:"jwt"`
+	SMTP     SMTP     `yaml:"smtp" toml:"smtp"`
+	Account  Account  `yaml:"account" toml:"account"`
+}
+
+// Default return configuration with default value.
+func Default() Config {
+	return Config{
+		Env: DEVELOPMENT,
+		Server: Server{
+			Port:           8000,
+			BaseURL:        "http://localhost:8000",
+			TrustedProxies: []string{"127.0.0.1"},
+		},
+		Database: Database{
+			Driver:          MYSQL,
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration{30 * time.Minute},
+			ConnMaxIdleTime: Duration{5 * time.Minute},
+			QueryTimeout:    Duration{10 * time.Second},
+		},
+		JWT: JWT{
+			Secret:     defaultSecretKey,
+			Issuer:     "kafekoding",
+			TTL:        Duration{15 * time.Minute},
+			RefreshTTL: Duration{30 * 24 * time.Hour},
+		},
+		SMTP: SMTP{
+			Port: 587,
+		},
+		Account: Account{
+			ActivationTTL:    Duration{24 * time.Hour},
+			PasswordResetTTL: Duration{time.Hour},
+			ResendCooldown:   Duration{time.Minute},
+			ResendLimit:      5,
+			PurgeInterval:    Duration{time.Hour},
+		},
+	}
+}
+
+// Load is function to load configuration. The value is read in order from
+// default, configuration file (yaml or toml), .env file and environment,
+// the later one override the previous one.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	// .env is optional, variable in environment is not overridden.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("config: load .env: %w", err)
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	cfg.Server.BaseURL = strings.TrimRight(cfg.Server.BaseURL, "/")
+	if cfg.Database.DSN == "" {
+		cfg.Database.DSN = defaultDSN[cfg.Database.Driver]
+	}
+	if cfg.Account.PasswordResetURL == "" {
+		cfg.Account.PasswordResetURL = cfg.Server.BaseURL + "/reset-password"
+	}
+	if cfg.Account.CheckInURL == "" {
+		cfg.Account.CheckInURL = cfg.Server.BaseURL + "/check-in"
+	}
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, cfg)
+	case ".toml":
+		err = toml.Unmarshal(content, cfg)
+	default:
+		return fmt.Errorf("config: unsupported file format %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate is function to check configuration value.
+func (c Config) Validate() error {
+	var errorMessages []string
+
+	switch c.Env {
+	case DEVELOPMENT, STAGING, PRODUCTION:
+	default:
+		errorMessages = append(errorMessages, fmt.Sprintf("env %q is not one of development, staging, production", c.Env))
+	}
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errorMessages = append(errorMessages, fmt.Sprintf("server port %d is out of range", c.Server.Port))
+	}
+	if c.Server.BaseURL == "" {
+		errorMessages = append(errorMessages, "server base url is required")
+	}
+	if c.Database.Driver == "" {
+		errorMessages = append(errorMessages, "database driver is required")
+	}
+	if c.Database.DSN == "" {
+		errorMessages = append(errorMessages, "database dsn is required")
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errorMessages = append(errorMessages, "database connection pool size must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errorMessages = append(errorMessages, "database max idle connections must not exceed max open connections")
+	}
+	if c.JWT.Secret == "" && c.JWT.KeysDir == "" {
+		errorMessages = append(errorMessages, "jwt secret or keys directory is required")
+	}
+	if c.JWT.TTL.Duration <= 0 {
+		errorMessages = append(errorMessages, "jwt ttl must be positive")
+	}
+	if c.JWT.RefreshTTL.Duration <= c.JWT.TTL.Duration {
+		errorMessages = append(errorMessages, "jwt refresh ttl must be longer than ttl")
+	}
+	if c.Env != DEVELOPMENT {
+		if c.JWT.Secret != "" && (c.JWT.Secret == defaultSecretKey || len(c.JWT.Secret) < 32) {
+			errorMessages = append(errorMessages, "jwt secret must be at least 32 characters outside development")
+		}
+		if c.SMTP.Server == "" {
+			errorMessages = append(errorMessages, "smtp server is required outside development")
+		}
+	}
+	if c.SMTP.Server != "" && (c.SMTP.Port < 1 || c.SMTP.Port > 65535) {
+		errorMessages = append(errorMessages, fmt.Sprintf("smtp port %d is out of range", c.SMTP.Port))
+	}
+
+	if c.Account.ActivationTTL.Duration <= 0 {
+		errorMessages = append(errorMessages, "account activation ttl must be positive")
+	}
+	if c.Account.PasswordResetTTL.Duration <= 0 {
+		errorMessages = append(errorMessages, "account password reset ttl must be positive")
+	}
+	if c.Account.ResendLimit < 1 {
+		errorMessages = append(errorMessages, "account resend limit must be at least 1")
+	}
+	if c.Account.PurgeInterval.Duration <= 0 {
+		errorMessages = append(errorMessages, "account purge interval must be positive")
+	}
+
+	if len(errorMessages) > 0 {
+		return fmt.Errorf("config: %s", strings.Join(errorMessages, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadEnv override configuration with value from environment variable.
+func loadEnv(cfg *Config) error {
+	binder := envBinder{}
+
+	binder.string("APP_ENV", &cfg.Env)
+
+	binder.string("SERVER_HOST", &cfg.Server.Host)
+	binder.int("SERVER_PORT", &cfg.Server.Port)
+	binder.string("BASE_URL", &cfg.Server.BaseURL)
+	binder.list("TRUSTED_PROXIES", &cfg.Server.TrustedProxies)
+
+	binder.string("DATABASE_DRIVER", &cfg.Database.Driver)
+	binder.string("DATABASE_DSN", &cfg.Database.DSN)
+	binder.int("DATABASE_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	binder.int("DATABASE_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	binder.duration("DATABASE_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+	binder.duration("DATABASE_CONN_MAX_IDLE_TIME", &cfg.Database.ConnMaxIdleTime)
+	binder.duration("DATABASE_QUERY_TIMEOUT", &cfg.Database.QueryTimeout)
+	binder.bool("DATABASE_AUTO_MIGRATE", &cfg.Database.AutoMigrate)
+
+	binder.string("JWT_SECRET", &cfg.JWT.Secret)
+	binder.string("JWT_KEYS_DIR", &cfg.JWT.KeysDir)
+	binder.string("JWT_SIGNING_KEY_ID", &cfg.JWT.SigningKeyID)
+	binder.string("JWT_ISSUER", &cfg.JWT.Issuer)
+	binder.duration("JWT_TTL", &cfg.JWT.TTL)
+	binder.duration("JWT_REFRESH_TTL", &cfg.JWT.RefreshTTL)
+
+	binder.string("SMTP_SERVER", &cfg.SMTP.Server)
+	binder.int("SMTP_PORT", &cfg.SMTP.Port)
+	binder.string("SMTP_USERNAME", &cfg.SMTP.Username)
+	binder.string("SMTP_PASSWORD", &cfg.SMTP.Password)
+	binder.string("SMTP_FROM", &cfg.SMTP.From)
+
+	binder.duration("ACCOUNT_ACTIVATION_TTL", &cfg.Account.ActivationTTL)
+	binder.duration("ACCOUNT_PASSWORD_RESET_TTL", &cfg.Account.PasswordResetTTL)
+	binder.string("ACCOUNT_PASSWORD_RESET_URL", &cfg.Account.PasswordResetURL)
+	binder.string("ACCOUNT_CHECK_IN_URL", &cfg.Account.CheckInURL)
+	binder.duration("ACCOUNT_RESEND_COOLDOWN", &cfg.Account.ResendCooldown)
+	binder.int("ACCOUNT_RESEND_LIMIT", &cfg.Account.ResendLimit)
+	binder.duration("ACCOUNT_PURGE_INTERVAL", &cfg.Account.PurgeInterval)
+
+	return binder.err
+}
+
+// envBinder read environment variable to a target and keep first error.
+type envBinder struct {
+	err error
+}
+
+func (b *envBinder) string(key string, target *string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*target = value
+	}
+}
+
+func (b *envBinder) list(key string, target *[]string) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	*target = result
+}
+
+func (b *envBinder) int(key string, target *int) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		b.fail(key, err)
+		return
+	}
+	*target = result
+}
+
+func (b *envBinder) bool(key string, target *bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		b.fail(key, err)
+		return
+	}
+	*target = result
+}
+
+func (b *envBinder) duration(key string, target *Duration) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		b.fail(key, err)
+		return
+	}
+	target.Duration = result
+}
+
+func (b *envBinder) fail(key string, err error) {
+	if b.err == nil {
+		b.err = fmt.Errorf("config: invalid %s: %w", key, err)
+	}
+}
@@ -2,19 +2,86 @@ package controllers
 
 import (
 	"github.com/Aeroxee/kafekoding-api/handlers"
+	"github.com/Aeroxee/kafekoding-api/middlewares"
+	"github.com/Aeroxee/kafekoding-api/policy"
 	"github.com/gin-gonic/gin"
 )
 
-func ClassControllerV1WithAuth(group *gin.RouterGroup) {
-	classHandlerV1 := handlers.NewClassHandlerV1()
-
-	group.POST("", classHandlerV1.CreateHandler)
-	group.GET("/:slug", classHandlerV1.Detail)
+func ClassControllerV1WithAuth(group *gin.RouterGroup, classHandlerV1 handlers.ClassHandlerV1) {
+	group.POST("", middlewares.Authorize(policy.CreateClass), classHandlerV1.CreateHandler)
 	group.PUT("/:slug", classHandlerV1.Update)
-	group.DELETE("/:slug", classHandlerV1.Delete)
+	group.DELETE("/:slug", middlewares.Authorize(policy.DeleteClass), classHandlerV1.Delete)
+
+	group.POST("/:slug/members", classHandlerV1.BulkAddMembersHandler)
+	group.DELETE("/:slug/members", classHandlerV1.BulkRemoveMembersHandler)
+	group.POST("/:slug/members/:username", classHandlerV1.AddMemberHandler)
+	group.DELETE("/:slug/members/:username", classHandlerV1.RemoveMemberHandler)
+
+	group.POST("/:slug/mentors", classHandlerV1.BulkAddMentorsHandler)
+	group.DELETE("/:slug/mentors", classHandlerV1.BulkRemoveMentorsHandler)
+	group.POST("/:slug/mentors/:username", classHandlerV1.AddMentorHandler)
+	group.DELETE("/:slug/mentors/:username", classHandlerV1.RemoveMentorHandler)
+
+	group.POST("/:slug/images", classHandlerV1.UploadImagesHandler)
+	group.POST("/:slug/images/reorder", classHandlerV1.ReorderImagesHandler)
+	group.PUT("/:slug/images/:id", classHandlerV1.UpdateImageHandler)
+	group.DELETE("/:slug/images/:id", classHandlerV1.DeleteImageHandler)
+
+	group.POST("/:slug/modules", classHandlerV1.CreateModuleHandler)
+	group.POST("/:slug/modules/reorder", classHandlerV1.ReorderModulesHandler)
+	group.PUT("/:slug/modules/:module", classHandlerV1.UpdateModuleHandler)
+	group.DELETE("/:slug/modules/:module", classHandlerV1.DeleteModuleHandler)
+	group.POST("/:slug/modules/:module/lessons", classHandlerV1.CreateLessonHandler)
+	group.POST("/:slug/modules/:module/lessons/reorder", classHandlerV1.ReorderLessonsHandler)
+	group.PUT("/:slug/lessons/:lesson", classHandlerV1.UpdateLessonHandler)
+	group.DELETE("/:slug/lessons/:lesson", classHandlerV1.DeleteLessonHandler)
+	group.POST("/:slug/lessons/:lesson/attachments", classHandlerV1.UploadAttachmentsHandler)
+	group.DELETE("/:slug/lessons/:lesson/attachments/:id", classHandlerV1.DeleteAttachmentHandler)
+
+	group.GET("/:slug/assignments", classHandlerV1.AssignmentsHandler)
+	group.POST("/:slug/assignments", classHandlerV1.CreateAssignmentHandler)
+	group.GET("/:slug/assignments/:assignment", classHandlerV1.AssignmentDetailHandler)
+	group.PUT("/:slug/assignments/:assignment", classHandlerV1.UpdateAssignmentHandler)
+	group.DELETE("/:slug/assignments/:assignment", classHandlerV1.DeleteAssignmentHandler)
+	group.POST("/:slug/assignments/:assignment/submissions", classHandlerV1.SubmitAssignmentHandler)
+	group.GET("/:slug/assignments/:assignment/submissions", classHandlerV1.SubmissionsHandler)
+	group.GET("/:slug/assignments/:assignment/submissions/:submission", classHandlerV1.SubmissionDetailHandler)
+	group.POST("/:slug/assignments/:assignment/submissions/:submission/grade", classHandlerV1.GradeSubmissionHandler)
+	group.POST("/:slug/assignments/:assignment/submissions/:submission/comments", classHandlerV1.CommentSubmissionHandler)
+
+	group.POST("/:slug/meetings", classHandlerV1.CreateMeetingHandler)
+	group.POST("/:slug/meetings/reorder", classHandlerV1.ReorderMeetingsHandler)
+	group.PUT("/:slug/meetings/:meeting", classHandlerV1.UpdateMeetingHandler)
+	group.DELETE("/:slug/meetings/:meeting", classHandlerV1.DeleteMeetingHandler)
+	group.PUT("/:slug/meetings/:meeting/following", classHandlerV1.UpdateFollowingMeetingsHandler)
+	group.GET("/:slug/meetings/:meeting/code", classHandlerV1.CheckInCodeHandler)
+	group.GET("/:slug/meetings/:meeting/qr.png", classHandlerV1.CheckInQRHandler)
+	group.POST("/:slug/meetings/:meeting/check-in", classHandlerV1.CheckInHandler)
+	group.GET("/:slug/attendance", classHandlerV1.AttendanceReportHandler)
+	group.GET("/:slug/meetings/:meeting/attendance", classHandlerV1.AttendanceHandler)
+	group.POST("/:slug/meetings/:meeting/attendance/:username", classHandlerV1.MarkAttendanceHandler)
+	group.DELETE("/:slug/meetings/:meeting/attendance/:username", classHandlerV1.UnmarkAttendanceHandler)
+
+	group.POST("/:slug/schedules", classHandlerV1.CreateScheduleHandler)
+	group.DELETE("/:slug/schedules/:id", classHandlerV1.DeleteScheduleHandler)
+
+	group.GET("/:slug/waitlist", classHandlerV1.WaitlistHandler)
+	group.DELETE("/:slug/waitlist/:username", classHandlerV1.RemoveWaitlistHandler)
+
+	group.POST("/:slug/requests", classHandlerV1.SubmitRequestHandler)
+	group.GET("/:slug/requests", classHandlerV1.RequestsHandler)
+	group.POST("/:slug/requests/:id/approve", classHandlerV1.ApproveRequestHandler)
+	group.POST("/:slug/requests/:id/reject", classHandlerV1.RejectRequestHandler)
 }
 
-func ClassControllerV1NoAuth(group *gin.RouterGroup) {
-	classHandlerV1 := handlers.NewClassHandlerV1()
+func ClassControllerV1NoAuth(group *gin.RouterGroup, classHandlerV1 handlers.ClassHandlerV1) {
 	group.GET("", classHandlerV1.Get)
+	group.GET("/:slug", classHandlerV1.Detail)
+	group.GET("/:slug/images", classHandlerV1.ImagesHandler)
+	group.GET("/:slug/curriculum", classHandlerV1.CurriculumHandler)
+	group.GET("/:slug/lessons/:lesson", classHandlerV1.LessonDetailHandler)
+	group.GET("/:slug/meetings", classHandlerV1.MeetingsHandler)
+	group.GET("/:slug/meetings.ics", classHandlerV1.MeetingsCalendarHandler)
+	group.GET("/:slug/meetings/:meeting", classHandlerV1.MeetingDetailHandler)
+	group.GET("/:slug/schedules", classHandlerV1.SchedulesHandler)
 }
@@ -5,9 +5,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func UserController(group *gin.RouterGroup) {
-	userHandler := handlers.NewUserHandlerV1()
+func UserController(group *gin.RouterGroup, userHandler handlers.UserHandlerV1) {
 	group.GET("/auth", userHandler.CheckAuthHandler)
 	group.PUT("/update-info", userHandler.UpdateInfoUserHandler)
 	group.POST("/change-password", userHandler.ChangePasswordHandler)
+	group.POST("/logout", userHandler.LogoutHandler)
+	group.POST("/logout-all", userHandler.LogoutAllHandler)
+	group.GET("/sessions", userHandler.SessionsHandler)
+	group.DELETE("/sessions/:id", userHandler.DeleteSessionHandler)
+	group.GET("/notifications", userHandler.NotificationsHandler)
+	group.POST("/notifications/read", userHandler.ReadNotificationHandler)
+	group.POST("/notifications/:id/read", userHandler.ReadNotificationHandler)
+	group.GET("/class-requests", userHandler.ClassRequestsHandler)
+	group.POST("/calendar", userHandler.CreateCalendarLinkHandler)
+	group.DELETE("/calendar", userHandler.DeleteCalendarLinkHandler)
 }
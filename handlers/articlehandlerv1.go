@@ -5,18 +5,25 @@ import (
 	"net/http"
 
 	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/gosimple/slug"
+	"gorm.io/gorm"
 )
 
-type ArticleHandlerV1 struct{}
+type ArticleHandlerV1 struct {
+	db *gorm.DB
+}
 
-func NewArticleHandlerV1() ArticleHandlerV1 {
-	return ArticleHandlerV1{}
+func NewArticleHandlerV1(db *gorm.DB) ArticleHandlerV1 {
+	return ArticleHandlerV1{
+		db: db,
+	}
 }
 
-func (ArticleHandlerV1) CreateHandler(ctx *gin.Context) {
+func (a ArticleHandlerV1) CreateHandler(ctx *gin.Context) {
+	db := a.db.WithContext(ctx.Request.Context())
 	payloads := struct {
 		Title   string               `json:"title" validate:"required"`
 		Content string               `json:"content" validate:"required"`
@@ -31,7 +38,6 @@ func (ArticleHandlerV1) CreateHandler(ctx *gin.Context) {
 		return
 	}
 
-	validate = validator.New(validator.WithRequiredStructEnabled())
 	err = validate.Struct(&payloads)
 	if err != nil {
 		if _, ok := err.(*validator.InvalidValidationError); ok {
@@ -53,7 +59,7 @@ func (ArticleHandlerV1) CreateHandler(ctx *gin.Context) {
 	}
 
 	// get this user info
-	thisUser, err := getUserFromContext(ctx.Request)
+	thisUser, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -70,7 +76,7 @@ func (ArticleHandlerV1) CreateHandler(ctx *gin.Context) {
 		Status:  payloads.Status,
 	}
 
-	articleModel := models.NewArticleModel(models.DB())
+	articleModel := models.NewArticleModel(db)
 	err = articleModel.CreateNewArticle(&article)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -87,7 +93,8 @@ func (ArticleHandlerV1) CreateHandler(ctx *gin.Context) {
 	})
 }
 
-func (ArticleHandlerV1) Get(ctx *gin.Context) {
+func (a ArticleHandlerV1) Get(ctx *gin.Context) {
+	db := a.db.WithContext(ctx.Request.Context())
 	page := getQueryInt(ctx.Request, "page", 1)
 	size := getQueryInt(ctx.Request, "size", 10)
 	status := getQueryString(ctx.Request, "status", "PUBLISHED")
@@ -95,11 +102,11 @@ func (ArticleHandlerV1) Get(ctx *gin.Context) {
 	// calculate offset based on page and size.
 	offset := (page - 1) * size
 
-	articleModel := models.NewArticleModel(models.DB())
+	articleModel := models.NewArticleModel(db)
 	articles := articleModel.GetAllArticle(models.ArticleStatus(status), size, offset)
 
 	var count int64
-	err := models.DB().Model(&models.Article{}).Count(&count).Error
+	err := db.Model(&models.Article{}).Count(&count).Error
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -116,8 +123,9 @@ func (ArticleHandlerV1) Get(ctx *gin.Context) {
 	})
 }
 
-func (ArticleHandlerV1) Update(ctx *gin.Context) {
-	thisUser, err := getUserFromContext(ctx.Request)
+func (a ArticleHandlerV1) Update(ctx *gin.Context) {
+	db := a.db.WithContext(ctx.Request.Context())
+	thisUser, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -127,7 +135,7 @@ func (ArticleHandlerV1) Update(ctx *gin.Context) {
 	}
 
 	slugArticle := ctx.Param("slug")
-	article, err := models.NewArticleModel(models.DB()).GetArticleBySlug(slugArticle)
+	article, err := models.NewArticleModel(db).GetArticleBySlug(slugArticle)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
@@ -136,8 +144,8 @@ func (ArticleHandlerV1) Update(ctx *gin.Context) {
 		return
 	}
 
-	if thisUser.ID != article.UserID {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+	if !policy.Can(thisUser, policy.UpdateArticle, article) {
+		ctx.JSON(http.StatusForbidden, gin.H{
 			"status":  "error",
 			"message": "You don't have permission to update this article.",
 		})
@@ -168,7 +176,7 @@ func (ArticleHandlerV1) Update(ctx *gin.Context) {
 	article.Status = models.ArticleStatus(payloads.Status)
 
 	// save
-	models.DB().Save(&article)
+	db.Save(&article)
 	ctx.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Update article successfully",
@@ -176,9 +184,10 @@ func (ArticleHandlerV1) Update(ctx *gin.Context) {
 	})
 }
 
-func (ArticleHandlerV1) Detail(ctx *gin.Context) {
+func (a ArticleHandlerV1) Detail(ctx *gin.Context) {
+	db := a.db.WithContext(ctx.Request.Context())
 	slugArticle := ctx.Param("slug")
-	article, err := models.NewArticleModel(models.DB()).GetArticleBySlug(slugArticle)
+	article, err := models.NewArticleModel(db).GetArticleBySlug(slugArticle)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
@@ -194,8 +203,9 @@ func (ArticleHandlerV1) Detail(ctx *gin.Context) {
 	})
 }
 
-func (ArticleHandlerV1) Delete(ctx *gin.Context) {
-	thisUser, err := getUserFromContext(ctx.Request)
+func (a ArticleHandlerV1) Delete(ctx *gin.Context) {
+	db := a.db.WithContext(ctx.Request.Context())
+	thisUser, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -205,7 +215,7 @@ func (ArticleHandlerV1) Delete(ctx *gin.Context) {
 	}
 
 	slugArticle := ctx.Param("slug")
-	article, err := models.NewArticleModel(models.DB()).GetArticleBySlug(slugArticle)
+	article, err := models.NewArticleModel(db).GetArticleBySlug(slugArticle)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
@@ -214,14 +224,14 @@ func (ArticleHandlerV1) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if thisUser.ID != article.UserID {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+	if !policy.Can(thisUser, policy.DeleteArticle, article) {
+		ctx.JSON(http.StatusForbidden, gin.H{
 			"status":  "error",
-			"message": "You don't have permission to update this article.",
+			"message": "You don't have permission to delete this article.",
 		})
 		return
 	}
 
-	models.DB().Delete(&article)
+	db.Delete(&article)
 	ctx.JSON(http.StatusNoContent, nil)
 }
@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+const (
+	// checkInLinkTTL is lifetime of check-in link in QR code.
+	checkInLinkTTL = 2 * time.Minute
+	// maxCheckInFailures is maximum wrong check-in code of user to a
+	// meeting in checkInFailureWindow.
+	maxCheckInFailures   = 5
+	checkInFailureWindow = 10 * time.Minute
+)
+
+// load meeting of class in request.
+func meetingForAttendance(db *gorm.DB, ctx *gin.Context, class models.Class) (models.ClassMeeting, bool) {
+	meeting, err := models.NewClassMeetingModel(db).GetMeetingBySlug(class.ID, ctx.Param("meeting"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Meeting not found.",
+		})
+		return meeting, false
+	}
+	return meeting, true
+}
+
+// CheckInCodeHandler is handler for mentor to get the current check-in code
+// of meeting, the code change every 30 seconds.
+func (c ClassHandlerV1) CheckInCodeHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+	meeting, ok := meetingForAttendance(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	attendance, err := models.NewClassMeetingAttendanceModel(db).GetAttendance(meeting.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"code":       auth.RotatingCode(attendance.Secret, now),
+		"expires_at": auth.CodeExpiresAt(now),
+		"is_open":    isMeetingOpen(meeting, now),
+		"opened_at":  meeting.OpenedAt,
+		"closed_at":  meeting.ClosedAt,
+	})
+}
+
+// CheckInHandler is handler for member of class to record attendance with
+// the code shown by mentor, it's accepted only while the meeting is open.
+func (c ClassHandlerV1) CheckInHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	thisUser, err := getUserFromContext(db, ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Authentication is required.",
+		})
+		return
+	}
+
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+	meeting, ok := meetingForAttendance(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	if !policy.Can(thisUser, policy.AttendMeeting, class) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Only member of this class can check in.",
+		})
+		return
+	}
+
+	// code shown by mentor, or token of check-in link in QR code.
+	payloads := struct {
+		Code  string `json:"code"`
+		Token string `json:"token"`
+	}{}
+	err = ctx.ShouldBindJSON(&payloads)
+	if err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if payloads.Token == "" {
+		payloads.Token = ctx.Query("token")
+	}
+	if payloads.Code == "" && payloads.Token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Code or token is required.",
+		})
+		return
+	}
+	if payloads.Token != "" {
+		meetingID, err := auth.VerifyCheckInToken(payloads.Token)
+		if err != nil || meetingID != meeting.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Check-in link is not valid or expired.",
+			})
+			return
+		}
+	}
+
+	now := time.Now()
+	if !isMeetingOpen(meeting, now) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Check-in is only open while the meeting is running.",
+		})
+		return
+	}
+
+	attendanceModel := models.NewClassMeetingAttendanceModel(db)
+	attendance, err := attendanceModel.GetAttendance(meeting.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if attendance.HasUser(thisUser.ID) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "You are already checked in.",
+		})
+		return
+	}
+
+	if payloads.Token == "" {
+		failures, err := attendanceModel.CountCheckInFailures(meeting.ID, thisUser.ID, now.Add(-checkInFailureWindow))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		if failures >= maxCheckInFailures {
+			log.Printf("check-in of user %d to meeting %d is limited", thisUser.ID, meeting.ID)
+			ctx.JSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "Too many wrong codes, please try again later.",
+			})
+			return
+		}
+
+		if !auth.VerifyRotatingCode(attendance.Secret, payloads.Code, now) {
+			if err := attendanceModel.AddCheckInFailure(meeting.ID, thisUser.ID); err != nil {
+				log.Printf("record check-in failure: %v", err)
+			}
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Code is not valid or expired.",
+			})
+			return
+		}
+	}
+
+	err = attendanceModel.AddUser(&attendance, &thisUser)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "You are checked in to " + meeting.Title + ".",
+	})
+}
+
+// CheckInQRHandler is handler for mentor to show QR code of signed check-in
+// link of meeting, the link open check-in page of frontend that send the
+// token with credential of the member. The link expire after checkInLinkTTL
+// so the QR code must be reloaded. Size of image is given with ?size=512.
+func (c ClassHandlerV1) CheckInQRHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+	meeting, ok := meetingForAttendance(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	token, expiresAt, err := auth.GetCheckInToken(meeting.ID, checkInLinkTTL)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	link := fmt.Sprintf("%s?class=%s&meeting=%s&token=%s", c.cfg.Account.CheckInURL,
+		url.QueryEscape(class.Slug), url.QueryEscape(meeting.Slug), url.QueryEscape(token))
+
+	size := min(max(getQueryInt(ctx.Request, "size", 512), 128), 1024)
+	image, err := qrcode.Encode(link, qrcode.Medium, size)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Expires", expiresAt.UTC().Format(http.TimeFormat))
+	ctx.Data(http.StatusOK, "image/png", image)
+}
+
+// AttendanceHandler is handler for mentor to list users that attend meeting.
+func (c ClassHandlerV1) AttendanceHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+	meeting, ok := meetingForAttendance(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	attendance, err := models.NewClassMeetingAttendanceModel(db).GetAttendance(meeting.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"attendance": attendance,
+	})
+}
+
+// MarkAttendanceHandler is handler for mentor to record attendance of member
+// manually, e.g. the member can't check in.
+func (c ClassHandlerV1) MarkAttendanceHandler(ctx *gin.Context) {
+	c.overrideAttendance(ctx, true)
+}
+
+// UnmarkAttendanceHandler is handler for mentor to remove attendance of user.
+func (c ClassHandlerV1) UnmarkAttendanceHandler(ctx *gin.Context) {
+	c.overrideAttendance(ctx, false)
+}
+
+func (c ClassHandlerV1) overrideAttendance(ctx *gin.Context, present bool) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+	meeting, ok := meetingForAttendance(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	username := ctx.Param("username")
+	user, err := models.NewUserModel(db).GetUserByUsername(username)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "User with username " + username + " is not found.",
+		})
+		return
+	}
+
+	attendanceModel := models.NewClassMeetingAttendanceModel(db)
+	attendance, err := attendanceModel.GetAttendance(meeting.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	switch {
+	case present && !class.IsMember(user.ID):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "User with username " + username + " is not member of this class.",
+		})
+		return
+	case present && attendance.HasUser(user.ID):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "User with username " + username + " is already checked in.",
+		})
+		return
+	case !present && !attendance.HasUser(user.ID):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "User with username " + username + " is not checked in.",
+		})
+		return
+	}
+
+	if present {
+		err = attendanceModel.AddUser(&attendance, &user)
+	} else {
+		err = attendanceModel.RemoveUser(&attendance, &user)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if present {
+		ctx.JSON(http.StatusCreated, gin.H{
+			"status":  "success",
+			"message": "User with username " + username + " is checked in.",
+		})
+		return
+	}
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// meeting is open from OpenedAt until ClosedAt.
+func isMeetingOpen(meeting models.ClassMeeting, now time.Time) bool {
+	return !now.Before(meeting.OpenedAt) && now.Before(meeting.ClosedAt)
+}
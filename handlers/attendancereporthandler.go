@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/recurrence"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// reportPeriod parse period of attendance report from query, it's either
+// ?month=2026-10 or ?from=2026-10-01&to=2026-10-31 (inclusive) in time zone
+// ?tz=Asia/Jakarta, default to UTC. The returned name is used in file name.
+func reportPeriod(ctx *gin.Context) (from, to *time.Time, loc *time.Location, name string, err error) {
+	loc = time.UTC
+	if tz := ctx.Query("tz"); tz != "" {
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, nil, nil, "", fmt.Errorf("time zone %q is not valid", tz)
+		}
+	}
+
+	if month := ctx.Query("month"); month != "" {
+		start, err := time.ParseInLocation("2006-01", month, loc)
+		if err != nil {
+			return nil, nil, nil, "", fmt.Errorf("month %q must be YYYY-MM", month)
+		}
+		end := start.AddDate(0, 1, 0)
+		return &start, &end, loc, month, nil
+	}
+
+	if value := ctx.Query("from"); value != "" {
+		t, err := time.ParseInLocation(recurrence.DateLayout, value, loc)
+		if err != nil {
+			return nil, nil, nil, "", fmt.Errorf("from %q must be YYYY-MM-DD", value)
+		}
+		from = &t
+		name = value
+	}
+	if value := ctx.Query("to"); value != "" {
+		t, err := time.ParseInLocation(recurrence.DateLayout, value, loc)
+		if err != nil {
+			return nil, nil, nil, "", fmt.Errorf("to %q must be YYYY-MM-DD", value)
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+		if name != "" {
+			name += "_"
+		}
+		name += value
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, nil, nil, "", fmt.Errorf("to must not be before from")
+	}
+	return from, to, loc, name, nil
+}
+
+// attendanceReportRows return attendance report as table with header, a row
+// for each member and total row. Date of meeting in header is in loc.
+func attendanceReportRows(report models.AttendanceReport, loc *time.Location) [][]any {
+	header := []any{"Username", "First name", "Last name", "Email", "Member"}
+	for _, meeting := range report.Meetings {
+		header = append(header, fmt.Sprintf("%s (%s)", meeting.Title, meeting.OpenedAt.In(loc).Format(recurrence.DateLayout)))
+	}
+	header = append(header, "Attended", "Percentage")
+	rows := [][]any{header}
+
+	for _, member := range report.Members {
+		row := []any{member.Username, member.FirstName, member.LastName, member.Email, member.IsMember}
+		for _, present := range member.Present {
+			if present {
+				row = append(row, 1)
+			} else {
+				row = append(row, 0)
+			}
+		}
+		rows = append(rows, append(row, member.Attended, member.Percentage))
+	}
+
+	total := []any{"Total", "", "", "", ""}
+	attended := 0
+	for _, meeting := range report.Meetings {
+		total = append(total, meeting.Attended)
+		attended += meeting.Attended
+	}
+	return append(rows, append(total, attended, report.Percentage))
+}
+
+// csvCell escape value that spreadsheet would run as formula, e.g. name of
+// user that start with "=".
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// AttendanceReportHandler is handler for mentor to get attendance of class
+// members in every meeting that already opened, with ?format=csv or
+// ?format=xlsx the report is downloaded as file.
+func (c ClassHandlerV1) AttendanceReportHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	from, to, loc, period, err := reportPeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	report, err := models.NewClassMeetingAttendanceModel(db).GetClassReport(class, from, to, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filename := class.Slug + "-attendance"
+	if period != "" {
+		filename += "-" + period
+	}
+
+	switch ctx.DefaultQuery("format", "json") {
+	case "json":
+		ctx.JSON(http.StatusOK, gin.H{
+			"status": "success",
+			"report": report,
+		})
+	case "csv":
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		for _, row := range attendanceReportRows(report, loc) {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				record[i] = csvCell(fmt.Sprint(cell))
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		ctx.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	case "xlsx":
+		file := excelize.NewFile()
+		defer file.Close()
+		sheet := "Attendance"
+		file.SetSheetName(file.GetSheetName(0), sheet)
+		for i, row := range attendanceReportRows(report, loc) {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			if err := file.SetSheetRow(sheet, cell, &row); err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"status":  "error",
+					"message": err.Error(),
+				})
+				return
+			}
+		}
+		file.SetPanes(sheet, &excelize.Panes{Freeze: true, XSplit: 1, YSplit: 1, TopLeftCell: "B2", ActivePane: "bottomRight"})
+
+		buf, err := file.WriteToBuffer()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".xlsx"))
+		ctx.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Format must be json, csv or xlsx.",
+		})
+	}
+}
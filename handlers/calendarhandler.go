@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/ical"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// calendarTokenTTL is lifetime of link of user calendar feed, it's replaced
+// when user create new link.
+const calendarTokenTTL = 10 * 365 * 24 * time.Hour
+
+// meetingEvents return meetings of class as calendar events, UID of the event
+// is based on meeting ID so it doesn't change when the meeting is edited.
+func meetingEvents(baseURL string, class models.Class) []ical.Event {
+	host := "kafekoding"
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	events := []ical.Event{}
+	for _, meeting := range class.Meetings {
+		events = append(events, ical.Event{
+			UID:         fmt.Sprintf("meeting-%d@%s", meeting.ID, host),
+			Summary:     fmt.Sprintf("%s: %s", class.Title, meeting.Title),
+			Description: meeting.Content,
+			URL:         fmt.Sprintf("%s/v1/classes/%s/meetings/%s", baseURL, class.Slug, meeting.Slug),
+			Start:       meeting.OpenedAt,
+			End:         meeting.ClosedAt,
+			Modified:    meeting.UpdatedAt,
+		})
+	}
+	return events
+}
+
+// MeetingsCalendarHandler is handler to get meetings of class as iCalendar
+// feed.
+func (c ClassHandlerV1) MeetingsCalendarHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	calendar := ical.Calendar{
+		Name:   class.Title,
+		Events: meetingEvents(c.cfg.Server.BaseURL, class),
+	}
+	ctx.Data(http.StatusOK, ical.ContentType, calendar.Bytes())
+}
+
+// CreateCalendarLinkHandler is handler to create link of calendar feed of
+// this user, previous link doesn't work anymore.
+func (u *UserHandlerV1) CreateCalendarLinkHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	token, err := models.NewUserTokenModel(db).CreateToken(userID, models.CALENDAR, calendarTokenTTL)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status": "success",
+		"url":    fmt.Sprintf("%s/v1/user/calendar/%s.ics", u.cfg.Server.BaseURL, token),
+	})
+}
+
+// DeleteCalendarLinkHandler is handler to disable link of calendar feed of
+// this user.
+func (u *UserHandlerV1) DeleteCalendarLinkHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	err := models.NewUserTokenModel(db).ExpireTokens(userID, models.CALENDAR)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Calendar link is disabled.",
+	})
+}
+
+// CalendarHandler is handler to get meetings of classes that user mentor or
+// is member of as iCalendar feed, the user is authenticated by token in the
+// link because calendar apps can't send authorization header.
+func (u *UserHandlerV1) CalendarHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	token, ok := strings.CutSuffix(ctx.Param("token"), ".ics")
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Calendar not found.",
+		})
+		return
+	}
+
+	userToken, err := models.NewUserTokenModel(db).FindToken(models.CALENDAR, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Calendar not found.",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	classes, err := models.NewClassModel(db).GetUserClasses(userToken.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	calendar := ical.Calendar{Name: "KafeKoding"}
+	for _, class := range classes {
+		calendar.Events = append(calendar.Events, meetingEvents(u.cfg.Server.BaseURL, class)...)
+	}
+	ctx.Header("Cache-Control", "private")
+	ctx.Data(http.StatusOK, ical.ContentType, calendar.Bytes())
+}
@@ -0,0 +1,662 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// load class of request for its staff or members, isStaff tell whether this
+// user can manage assignments of the class.
+func classForAssignment(db *gorm.DB, ctx *gin.Context) (class models.Class, thisUser models.User, isStaff bool, ok bool) {
+	class, thisUser, ok = loadClassFor(db, ctx, "Only mentor and member of this class can see assignments.",
+		policy.ManageAssignments, policy.SubmitAssignment)
+	if !ok {
+		return class, thisUser, false, false
+	}
+	return class, thisUser, policy.Can(thisUser, policy.ManageAssignments, class), true
+}
+
+// load class of request and check that this user is mentor of the class.
+func classForManageAssignment(db *gorm.DB, ctx *gin.Context) (models.Class, models.User, bool) {
+	class, thisUser, isStaff, ok := classForAssignment(db, ctx)
+	if ok && !isStaff {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Only mentor of this class can manage assignments.",
+		})
+		return class, thisUser, false
+	}
+	return class, thisUser, ok
+}
+
+// load assignment of class in request.
+func assignmentForClass(db *gorm.DB, ctx *gin.Context, class models.Class) (models.ClassAssignment, bool) {
+	id, err := strconv.Atoi(ctx.Param("assignment"))
+	if err == nil {
+		assignment, err := models.NewClassAssignmentModel(db).GetAssignment(class.ID, id)
+		if err == nil {
+			return assignment, true
+		}
+	}
+	ctx.JSON(http.StatusNotFound, gin.H{
+		"status":  "error",
+		"message": "Assignment not found.",
+	})
+	return models.ClassAssignment{}, false
+}
+
+// load submission of assignment in request, member can only get their own
+// submission.
+func submissionForUser(db *gorm.DB, ctx *gin.Context, assignment models.ClassAssignment, thisUser models.User, isStaff bool) (models.ClassSubmission, bool) {
+	id, err := strconv.Atoi(ctx.Param("submission"))
+	if err == nil {
+		submission, err := models.NewClassAssignmentModel(db).GetSubmission(assignment.ID, id)
+		if err == nil && (isStaff || submission.UserID == thisUser.ID) {
+			return submission, true
+		}
+	}
+	ctx.JSON(http.StatusNotFound, gin.H{
+		"status":  "error",
+		"message": "Submission not found.",
+	})
+	return models.ClassSubmission{}, false
+}
+
+// isValidLateRule check late submission rule of assignment.
+func isValidLateRule(assignment models.ClassAssignment) error {
+	if assignment.LatePenalty < 0 || assignment.LatePenalty > 100 {
+		return errors.New("Late penalty must be between 0 and 100.")
+	}
+	if assignment.LateUntil != nil && !assignment.LateUntil.After(assignment.DueAt) {
+		return errors.New("Late submission must close after the due time.")
+	}
+	return nil
+}
+
+// AssignmentsHandler is handler to list assignments of class by due date.
+func (c ClassHandlerV1) AssignmentsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, _, _, ok := classForAssignment(db, ctx)
+	if !ok {
+		return
+	}
+
+	assignments, err := models.NewClassAssignmentModel(db).GetClassAssignments(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":      "success",
+		"assignments": assignments,
+	})
+}
+
+// AssignmentDetailHandler is handler to get assignment of class, member also
+// get their submission.
+func (c ClassHandlerV1) AssignmentDetailHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, thisUser, _, ok := classForAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	var submission *models.ClassSubmission
+	own, err := models.NewClassAssignmentModel(db).GetUserSubmission(assignment.ID, thisUser.ID)
+	if err == nil {
+		submission = &own
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"assignment": assignment,
+		"submission": submission,
+	})
+}
+
+// CreateAssignmentHandler is handler for mentor to give assignment to class,
+// members are notified.
+func (c ClassHandlerV1) CreateAssignmentHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, _, ok := classForManageAssignment(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title        string     `json:"title" validate:"required,max=100"`
+		Instructions string     `json:"instructions"`
+		Meeting      string     `json:"meeting"`
+		DueAt        time.Time  `json:"due_at" validate:"required"`
+		MaxScore     int        `json:"max_score" validate:"required,min=1"`
+		AllowLate    bool       `json:"allow_late"`
+		LateUntil    *time.Time `json:"late_until"`
+		LatePenalty  int        `json:"late_penalty"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+	meetingID, ok := classMeeting(db, ctx, class, payloads.Meeting)
+	if !ok {
+		return
+	}
+
+	assignment := models.ClassAssignment{
+		ClassID:      class.ID,
+		MeetingID:    meetingID,
+		Title:        payloads.Title,
+		Instructions: payloads.Instructions,
+		DueAt:        payloads.DueAt,
+		MaxScore:     payloads.MaxScore,
+		AllowLate:    payloads.AllowLate,
+		LateUntil:    payloads.LateUntil,
+		LatePenalty:  payloads.LatePenalty,
+	}
+	if err := isValidLateRule(assignment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	assignmentModel := models.NewClassAssignmentModel(db)
+	err = assignmentModel.CreateAssignment(&assignment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	members := make([]models.User, 0, len(class.Members))
+	for _, member := range class.Members {
+		members = append(members, *member)
+	}
+	notify(db, c.mailer, members, "New assignment in "+class.Title,
+		fmt.Sprintf("%s is due at %s.", assignment.Title, assignment.DueAt.UTC().Format(time.RFC1123)))
+
+	assignment, _ = assignmentModel.GetAssignment(class.ID, assignment.ID)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":     "success",
+		"assignment": assignment,
+	})
+}
+
+// UpdateAssignmentHandler is handler for mentor to change assignment, empty
+// meeting or late_until remove it.
+func (c ClassHandlerV1) UpdateAssignmentHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, _, ok := classForManageAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title        *string    `json:"title" validate:"omitnil,min=1,max=100"`
+		Instructions *string    `json:"instructions"`
+		Meeting      *string    `json:"meeting"`
+		DueAt        *time.Time `json:"due_at"`
+		MaxScore     *int       `json:"max_score" validate:"omitnil,min=1"`
+		AllowLate    *bool      `json:"allow_late"`
+		LateUntil    *string    `json:"late_until"`
+		LatePenalty  *int       `json:"late_penalty"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+
+	if payloads.Title != nil {
+		assignment.Title = *payloads.Title
+	}
+	if payloads.Instructions != nil {
+		assignment.Instructions = *payloads.Instructions
+	}
+	if payloads.Meeting != nil {
+		meetingID, ok := classMeeting(db, ctx, class, *payloads.Meeting)
+		if !ok {
+			return
+		}
+		assignment.MeetingID = meetingID
+	}
+	if payloads.DueAt != nil {
+		assignment.DueAt = *payloads.DueAt
+	}
+	if payloads.MaxScore != nil {
+		assignment.MaxScore = *payloads.MaxScore
+	}
+	if payloads.AllowLate != nil {
+		assignment.AllowLate = *payloads.AllowLate
+	}
+	if payloads.LateUntil != nil {
+		assignment.LateUntil = nil
+		if *payloads.LateUntil != "" {
+			lateUntil, err := time.Parse(time.RFC3339, *payloads.LateUntil)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"status":  "error",
+					"message": "Late until must be RFC3339 time.",
+				})
+				return
+			}
+			assignment.LateUntil = &lateUntil
+		}
+	}
+	if payloads.LatePenalty != nil {
+		assignment.LatePenalty = *payloads.LatePenalty
+	}
+	if err := isValidLateRule(assignment); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	assignmentModel := models.NewClassAssignmentModel(db)
+	err = assignmentModel.UpdateAssignment(&assignment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	assignment, _ = assignmentModel.GetAssignment(class.ID, assignment.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"assignment": assignment,
+	})
+}
+
+// DeleteAssignmentHandler is handler for mentor to delete assignment.
+func (c ClassHandlerV1) DeleteAssignmentHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, _, ok := classForManageAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	err := models.NewClassAssignmentModel(db).DeleteAssignment(assignment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// SubmitAssignmentHandler is handler for member to submit assignment with
+// "link", "note" and "files" in multipart form. Submitting again replace the
+// previous submission until it's graded.
+func (c ClassHandlerV1) SubmitAssignmentHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, thisUser, _, ok := classForAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	if !policy.Can(thisUser, policy.SubmitAssignment, class) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Only member of this class can submit assignment.",
+		})
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if !assignment.AcceptsAt(now) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Submission of this assignment is closed.",
+		})
+		return
+	}
+
+	assignmentModel := models.NewClassAssignmentModel(db)
+	previous, err := assignmentModel.GetUserSubmission(assignment.ID, thisUser.ID)
+	if err == nil && previous.GradedAt != nil {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Submission is already graded.",
+		})
+		return
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload type is not multipart/form-data",
+		})
+		return
+	}
+	link := strings.TrimSpace(ctx.PostForm("link"))
+	files := form.File["files"]
+	if link == "" && len(files) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Please give a link or upload files.",
+		})
+		return
+	}
+	if link != "" {
+		if len(link) > 255 || validate.Var(link, "http_url") != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Link must be http or https URL.",
+			})
+			return
+		}
+	}
+	if len(files) > maxUploadFiles {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("Please upload at most %d files.", maxUploadFiles),
+		})
+		return
+	}
+
+	// check every file before saving any of them.
+	for _, file := range files {
+		if !isAllowedAttachment(strings.ToLower(filepath.Ext(file.Filename))) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "File type of " + file.Filename + " is not allowed.",
+			})
+			return
+		}
+		if file.Size > maxAttachmentSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": fmt.Sprintf("File %s is larger than %d MB.", file.Filename, maxAttachmentSize>>20),
+			})
+			return
+		}
+	}
+
+	submissionFiles := make([]models.ClassSubmissionFile, 0, len(files))
+	for _, file := range files {
+		filename := uuid.NewString() + strings.ToLower(filepath.Ext(file.Filename))
+		destination := fmt.Sprintf("media/classes/%s/submissions/%s", class.Slug, filename)
+		err := ctx.SaveUploadedFile(file, destination)
+		if err != nil {
+			for _, submissionFile := range submissionFiles {
+				removeClassFile(submissionFile.File)
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		submissionFiles = append(submissionFiles, models.ClassSubmissionFile{
+			File: destination,
+			Name: filepath.Base(file.Filename),
+			Size: file.Size,
+		})
+	}
+
+	submission := models.ClassSubmission{
+		AssignmentID: assignment.ID,
+		UserID:       thisUser.ID,
+		Note:         ctx.PostForm("note"),
+		SubmittedAt:  now,
+		IsLate:       assignment.IsLate(now),
+	}
+	if link != "" {
+		submission.Link = &link
+	}
+	old, err := assignmentModel.Submit(&submission, submissionFiles)
+	if err != nil {
+		for _, submissionFile := range submissionFiles {
+			removeClassFile(submissionFile.File)
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	for _, submissionFile := range old {
+		removeClassFile(submissionFile.File)
+	}
+
+	submission, _ = assignmentModel.GetSubmission(assignment.ID, submission.ID)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":     "success",
+		"submission": submission,
+	})
+}
+
+// SubmissionsHandler is handler for mentor to list submissions of
+// assignment.
+func (c ClassHandlerV1) SubmissionsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, _, ok := classForManageAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	submissions, err := models.NewClassAssignmentModel(db).GetSubmissions(assignment.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":      "success",
+		"submissions": submissions,
+	})
+}
+
+// SubmissionDetailHandler is handler to get submission with its comments,
+// for mentor or the member that submit it.
+func (c ClassHandlerV1) SubmissionDetailHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, thisUser, isStaff, ok := classForAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+	submission, ok := submissionForUser(db, ctx, assignment, thisUser, isStaff)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"submission": submission,
+	})
+}
+
+// GradeSubmissionHandler is handler for mentor to give score to submission
+// with optional feedback comment, late penalty is applied to the final
+// score and the member is notified.
+func (c ClassHandlerV1) GradeSubmissionHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, thisUser, ok := classForManageAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+	submission, ok := submissionForUser(db, ctx, assignment, thisUser, true)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Score   *int   `json:"score" validate:"required"`
+		Comment string `json:"comment"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+	if *payloads.Score < 0 || *payloads.Score > assignment.MaxScore {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("Score must be between 0 and %d.", assignment.MaxScore),
+		})
+		return
+	}
+
+	assignmentModel := models.NewClassAssignmentModel(db)
+	err = assignmentModel.Grade(assignment, &submission, *payloads.Score, thisUser.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	if comment := strings.TrimSpace(payloads.Comment); comment != "" {
+		err = assignmentModel.AddComment(&models.ClassSubmissionComment{
+			SubmissionID: submission.ID,
+			UserID:       thisUser.ID,
+			Message:      comment,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	if submission.User != nil {
+		notify(db, c.mailer, []models.User{*submission.User}, "Your submission of "+assignment.Title+" is graded",
+			fmt.Sprintf("Your score is %d of %d.", *submission.FinalScore, assignment.MaxScore))
+	}
+
+	submission, _ = assignmentModel.GetSubmission(assignment.ID, submission.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"submission": submission,
+	})
+}
+
+// CommentSubmissionHandler is handler for mentor or the member that submit
+// it to comment on submission.
+func (c ClassHandlerV1) CommentSubmissionHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, thisUser, isStaff, ok := classForAssignment(db, ctx)
+	if !ok {
+		return
+	}
+	assignment, ok := assignmentForClass(db, ctx, class)
+	if !ok {
+		return
+	}
+	submission, ok := submissionForUser(db, ctx, assignment, thisUser, isStaff)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Message string `json:"message" validate:"required"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+
+	comment := models.ClassSubmissionComment{
+		SubmissionID: submission.ID,
+		UserID:       thisUser.ID,
+		Message:      payloads.Message,
+	}
+	err = models.NewClassAssignmentModel(db).AddComment(&comment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	comment.User = &thisUser
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"comment": comment,
+	})
+}
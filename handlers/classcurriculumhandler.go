@@ -0,0 +1,601 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxAttachmentSize is maximum size of file attached to lesson.
+const maxAttachmentSize = 20 << 20
+
+// load class of request and check that this user is mentor of the class.
+func classForCurriculum(db *gorm.DB, ctx *gin.Context) (models.Class, bool) {
+	class, _, ok := loadClassFor(db, ctx, "Only mentor of this class can manage curriculum.", policy.ManageCurriculum)
+	return class, ok
+}
+
+// load module of class in request.
+func moduleForCurriculum(db *gorm.DB, ctx *gin.Context, class models.Class) (models.ClassModule, bool) {
+	id, err := strconv.Atoi(ctx.Param("module"))
+	if err == nil {
+		module, err := models.NewClassCurriculumModel(db).GetModule(class.ID, id)
+		if err == nil {
+			return module, true
+		}
+	}
+	ctx.JSON(http.StatusNotFound, gin.H{
+		"status":  "error",
+		"message": "Module not found.",
+	})
+	return models.ClassModule{}, false
+}
+
+// load lesson of class in request.
+func lessonForCurriculum(db *gorm.DB, ctx *gin.Context, class models.Class) (models.ClassLesson, bool) {
+	id, err := strconv.Atoi(ctx.Param("lesson"))
+	if err == nil {
+		lesson, err := models.NewClassCurriculumModel(db).GetLesson(class.ID, id)
+		if err == nil {
+			return lesson, true
+		}
+	}
+	ctx.JSON(http.StatusNotFound, gin.H{
+		"status":  "error",
+		"message": "Lesson not found.",
+	})
+	return models.ClassLesson{}, false
+}
+
+// CurriculumHandler is handler to get modules of class with their lessons
+// in order.
+func (c ClassHandlerV1) CurriculumHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	modules, err := models.NewClassCurriculumModel(db).GetCurriculum(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"modules": modules,
+	})
+}
+
+// LessonDetailHandler is handler to get lesson of class.
+func (c ClassHandlerV1) LessonDetailHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+	lesson, ok := lessonForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"lesson": lesson,
+	})
+}
+
+// CreateModuleHandler is handler to add module at the end of curriculum.
+func (c ClassHandlerV1) CreateModuleHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title       string `json:"title" validate:"required,max=100"`
+		Description string `json:"description"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+
+	module := models.ClassModule{
+		ClassID:     class.ID,
+		Title:       payloads.Title,
+		Description: payloads.Description,
+		Lessons:     []models.ClassLesson{},
+	}
+	err = models.NewClassCurriculumModel(db).CreateModule(&module)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status": "success",
+		"module": module,
+	})
+}
+
+// UpdateModuleHandler is handler to change title or description of module.
+func (c ClassHandlerV1) UpdateModuleHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	module, ok := moduleForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title       *string `json:"title" validate:"omitnil,min=1,max=100"`
+		Description *string `json:"description"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+
+	if payloads.Title != nil {
+		module.Title = *payloads.Title
+	}
+	if payloads.Description != nil {
+		module.Description = *payloads.Description
+	}
+	err = models.NewClassCurriculumModel(db).UpdateModule(&module)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"module": module,
+	})
+}
+
+// ReorderModulesHandler is handler to set order of modules, every module of
+// the class must be given by its id.
+func (c ClassHandlerV1) ReorderModulesHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Modules []int `json:"modules"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	curriculumModel := models.NewClassCurriculumModel(db)
+	modules, err := curriculumModel.GetCurriculum(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	existing := make([]int, len(modules))
+	for i, module := range modules {
+		existing[i] = module.ID
+	}
+	if !reorderIDs(ctx, "Module", existing, payloads.Modules) {
+		return
+	}
+
+	err = curriculumModel.ReorderModules(class.ID, payloads.Modules)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	modules, _ = curriculumModel.GetCurriculum(class.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"modules": modules,
+	})
+}
+
+// DeleteModuleHandler is handler to delete module with its lessons and their
+// files.
+func (c ClassHandlerV1) DeleteModuleHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	module, ok := moduleForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	attachments, err := models.NewClassCurriculumModel(db).DeleteModule(module)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	for _, attachment := range attachments {
+		removeClassFile(attachment.File)
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// CreateLessonHandler is handler to add lesson at the end of module, the
+// lesson can be linked to meeting by its slug.
+func (c ClassHandlerV1) CreateLessonHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	module, ok := moduleForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title   string `json:"title" validate:"required,max=100"`
+		Content string `json:"content"`
+		Meeting string `json:"meeting"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+	meetingID, ok := classMeeting(db, ctx, class, payloads.Meeting)
+	if !ok {
+		return
+	}
+
+	curriculumModel := models.NewClassCurriculumModel(db)
+	lesson := models.ClassLesson{
+		ModuleID:  module.ID,
+		MeetingID: meetingID,
+		Title:     payloads.Title,
+		Content:   payloads.Content,
+	}
+	err = curriculumModel.CreateLesson(&lesson)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	lesson, _ = curriculumModel.GetLesson(class.ID, lesson.ID)
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status": "success",
+		"lesson": lesson,
+	})
+}
+
+// UpdateLessonHandler is handler to change title, content or meeting of
+// lesson, empty meeting remove the link.
+func (c ClassHandlerV1) UpdateLessonHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	lesson, ok := lessonForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title   *string `json:"title" validate:"omitnil,min=1,max=100"`
+		Content *string `json:"content"`
+		Meeting *string `json:"meeting"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+
+	if payloads.Title != nil {
+		lesson.Title = *payloads.Title
+	}
+	if payloads.Content != nil {
+		lesson.Content = *payloads.Content
+	}
+	if payloads.Meeting != nil {
+		meetingID, ok := classMeeting(db, ctx, class, *payloads.Meeting)
+		if !ok {
+			return
+		}
+		lesson.MeetingID = meetingID
+	}
+
+	curriculumModel := models.NewClassCurriculumModel(db)
+	err = curriculumModel.UpdateLesson(&lesson)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	lesson, _ = curriculumModel.GetLesson(class.ID, lesson.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"lesson": lesson,
+	})
+}
+
+// ReorderLessonsHandler is handler to set order of lessons in module, every
+// lesson of the module must be given by its id.
+func (c ClassHandlerV1) ReorderLessonsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	module, ok := moduleForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Lessons []int `json:"lessons"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	existing := make([]int, len(module.Lessons))
+	for i, lesson := range module.Lessons {
+		existing[i] = lesson.ID
+	}
+	if !reorderIDs(ctx, "Lesson", existing, payloads.Lessons) {
+		return
+	}
+
+	curriculumModel := models.NewClassCurriculumModel(db)
+	err = curriculumModel.ReorderLessons(module.ID, payloads.Lessons)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	module, _ = curriculumModel.GetModule(class.ID, module.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"module": module,
+	})
+}
+
+// DeleteLessonHandler is handler to delete lesson with its files.
+func (c ClassHandlerV1) DeleteLessonHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	lesson, ok := lessonForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	attachments, err := models.NewClassCurriculumModel(db).DeleteLesson(lesson)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	for _, attachment := range attachments {
+		removeClassFile(attachment.File)
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// UploadAttachmentsHandler is handler to attach files to lesson, the files
+// are sent in "files" field of multipart form.
+func (c ClassHandlerV1) UploadAttachmentsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	lesson, ok := lessonForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload type is not multipart/form-data",
+		})
+		return
+	}
+	files := form.File["files"]
+	if len(files) == 0 || len(files) > maxUploadFiles {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("Please upload 1 to %d files.", maxUploadFiles),
+		})
+		return
+	}
+
+	// check every file before saving any of them.
+	for _, file := range files {
+		if !isAllowedAttachment(strings.ToLower(filepath.Ext(file.Filename))) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "File type of " + file.Filename + " is not allowed.",
+			})
+			return
+		}
+		if file.Size > maxAttachmentSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": fmt.Sprintf("File %s is larger than %d MB.", file.Filename, maxAttachmentSize>>20),
+			})
+			return
+		}
+	}
+
+	attachments := make([]models.ClassLessonAttachment, 0, len(files))
+	for _, file := range files {
+		filename := uuid.NewString() + strings.ToLower(filepath.Ext(file.Filename))
+		destination := fmt.Sprintf("media/classes/%s/lessons/%s", class.Slug, filename)
+		err := ctx.SaveUploadedFile(file, destination)
+		if err != nil {
+			for _, attachment := range attachments {
+				removeClassFile(attachment.File)
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		attachments = append(attachments, models.ClassLessonAttachment{
+			File: destination,
+			Name: filepath.Base(file.Filename),
+			Size: file.Size,
+		})
+	}
+
+	err = models.NewClassCurriculumModel(db).AddAttachments(lesson, attachments)
+	if err != nil {
+		for _, attachment := range attachments {
+			removeClassFile(attachment.File)
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":      "success",
+		"attachments": attachments,
+	})
+}
+
+// DeleteAttachmentHandler is handler to delete file of lesson.
+func (c ClassHandlerV1) DeleteAttachmentHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForCurriculum(db, ctx)
+	if !ok {
+		return
+	}
+	lesson, ok := lessonForCurriculum(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	curriculumModel := models.NewClassCurriculumModel(db)
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	attachment, err := curriculumModel.GetAttachment(lesson.ID, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Attachment not found.",
+		})
+		return
+	}
+
+	err = curriculumModel.DeleteAttachment(attachment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	removeClassFile(attachment.File)
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
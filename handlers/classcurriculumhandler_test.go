@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"gorm.io/gorm"
+)
+
+// createClass create class golang-dasar owned by owner with the given members.
+func createClass(t *testing.T, db *gorm.DB, owner models.User, members ...models.User) models.Class {
+	t.Helper()
+	class := models.Class{
+		Title:    "Golang Dasar",
+		Slug:     "golang-dasar",
+		IsActive: true,
+		OwnerID:  &owner.ID,
+		Mentors:  []*models.User{&owner},
+	}
+	for i := range members {
+		class.Members = append(class.Members, &members[i])
+	}
+	if err := models.NewClassModel(db).CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+	return class
+}
+
+func TestCreateModuleHandler(t *testing.T) {
+	r, db := newServer(t)
+	mentor := createUser(t, db, "mentor", models.MENTOR)
+	member := createUser(t, db, "member", models.MEMBER)
+	createClass(t, db, mentor, member)
+
+	tests := []struct {
+		name     string
+		username string
+		payload  map[string]string
+		want     int
+	}{
+		{"mentor", "mentor", map[string]string{"title": "Dasar"}, http.StatusCreated},
+		{"member", "member", map[string]string{"title": "Dasar"}, http.StatusForbidden},
+		{"without title", "mentor", map[string]string{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := login(t, r, tt.username)
+			code, body := request(t, r, http.MethodPost, "/v1/classes/golang-dasar/modules", token, tt.payload)
+			if code != tt.want {
+				t.Fatalf("got %d %v, want %d", code, body, tt.want)
+			}
+		})
+	}
+
+	code, body := request(t, r, http.MethodGet, "/v1/classes/golang-dasar/curriculum", "", nil)
+	if code != http.StatusOK {
+		t.Fatalf("curriculum got %d %v", code, body)
+	}
+	if modules := body["modules"].([]any); len(modules) != 1 {
+		t.Fatalf("got %d modules, want 1", len(modules))
+	}
+}
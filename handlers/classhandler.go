@@ -7,49 +7,50 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/Aeroxee/kafekoding-api/mailer"
 	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
+	"gorm.io/gorm"
 )
 
-type ClassHandlerV1 struct{}
+type ClassHandlerV1 struct {
+	db     *gorm.DB
+	cfg    config.Config
+	mailer *mailer.Mailer
+}
 
-func NewClassHandlerV1() ClassHandlerV1 {
-	return ClassHandlerV1{}
+func NewClassHandlerV1(db *gorm.DB, cfg config.Config, mailer *mailer.Mailer) ClassHandlerV1 {
+	return ClassHandlerV1{
+		db:     db,
+		cfg:    cfg,
+		mailer: mailer,
+	}
 }
 
 // CreateHandler is function to handler creating class.
 func (c ClassHandlerV1) CreateHandler(ctx *gin.Context) {
-	user, err := getUserFromContext(ctx.Request)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Authentication required",
-		})
-		return
-	}
-
-	if user.Type != models.ADMIN {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Access danied",
-		})
-		return
-	}
-
+	db := c.db.WithContext(ctx.Request.Context())
 	payloads := struct {
 		Title string `form:"title" validate:"required"`
 		// Slug        string                `form:"slug" validate:"required"`
 		Description string                `form:"description" validate:"required"`
 		Logo        *multipart.FileHeader `form:"logo" validate:"required"`
 		IsActive    bool                  `form:"is_active"`
+		Owner       string                `form:"owner"`
+		Capacity    int                   `form:"capacity" validate:"min=0"`
+		// enrollment window in RFC3339, e.g. 2026-01-02T15:04:05+07:00
+		EnrollmentOpensAt  *time.Time `form:"enrollment_opens_at"`
+		EnrollmentClosesAt *time.Time `form:"enrollment_closes_at"`
 	}{}
-	err = ctx.ShouldBindWith(&payloads, binding.FormMultipart)
+	err := ctx.ShouldBindWith(&payloads, binding.FormMultipart)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -58,7 +59,6 @@ func (c ClassHandlerV1) CreateHandler(ctx *gin.Context) {
 		return
 	}
 
-	validate = validator.New(validator.WithRequiredStructEnabled())
 	err = validate.Struct(&payloads)
 	if err != nil {
 		if _, ok := err.(*validator.InvalidValidationError); ok {
@@ -82,10 +82,40 @@ func (c ClassHandlerV1) CreateHandler(ctx *gin.Context) {
 	newSlug := slug.MakeLang(payloads.Title, "id")
 
 	class := models.Class{
-		Title:       payloads.Title,
-		Slug:        newSlug,
-		Description: payloads.Description,
-		IsActive:    payloads.IsActive,
+		Title:              payloads.Title,
+		Slug:               newSlug,
+		Description:        payloads.Description,
+		IsActive:           payloads.IsActive,
+		Capacity:           payloads.Capacity,
+		EnrollmentOpensAt:  nilIfZero(payloads.EnrollmentOpensAt),
+		EnrollmentClosesAt: nilIfZero(payloads.EnrollmentClosesAt),
+	}
+	if !isValidEnrollmentWindow(class) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Enrollment close time must be after the open time.",
+		})
+		return
+	}
+
+	// owner mentor of class, without owner the class is managed by admin.
+	var owner models.User
+	if payloads.Owner != "" {
+		owner, err = models.NewUserModel(db).GetUserByUsername(payloads.Owner)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "User with username " + payloads.Owner + " is not found.",
+			})
+			return
+		}
+		if !policy.CanMentor(owner) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "User with username " + payloads.Owner + " is not a mentor.",
+			})
+			return
+		}
 	}
 
 	// check extension file
@@ -112,7 +142,7 @@ func (c ClassHandlerV1) CreateHandler(ctx *gin.Context) {
 	class.Logo = &destination
 
 	// save to db
-	err = models.CreateNewClass(&class)
+	err = models.NewClassModel(db).CreateNewClass(&class)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -121,26 +151,34 @@ func (c ClassHandlerV1) CreateHandler(ctx *gin.Context) {
 		return
 	}
 
+	if payloads.Owner != "" {
+		err = models.NewClassModel(db).AddMentor(&class, &owner)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusCreated, class)
 }
 
 // Get is handler with request GET.
 func (c ClassHandlerV1) Get(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
 	is_active := getQueryBool(ctx.Request, "is_active", true)
 
-	classes := models.GetAllClass(is_active)
+	classes := models.NewClassModel(db).GetAllClass(is_active)
 	ctx.JSON(http.StatusOK, classes)
 }
 
 // Detail is handler to get detail of class.
 func (c ClassHandlerV1) Detail(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
 	slugClass := ctx.Param("slug")
-	add_mentor := ctx.QueryArray("add_mentor")
-	add_member := ctx.QueryArray("add_member")
-	delete_mentor := ctx.QueryArray("delete_mentor")
-	delete_member := ctx.QueryArray("delete_member")
-
-	class, err := models.GetClassBySlug(slugClass)
+	class, err := models.NewClassModel(db).GetClassBySlug(slugClass)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
@@ -149,205 +187,13 @@ func (c ClassHandlerV1) Detail(ctx *gin.Context) {
 		return
 	}
 
-	// add mentor
-	if len(add_mentor) > 0 {
-		isUser, err := getUserFromContext(ctx.Request)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Authentication is required.",
-			})
-			return
-		}
-
-		// check if this user is mentor for this class.
-		for _, m := range class.Mentors {
-			if m.Username != isUser.Username {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"status":  "error",
-					"message": "This method is not permitted",
-				})
-				return
-			}
-		}
-
-		var users []*models.User
-		for _, m := range add_mentor {
-			user, err := models.GetUserByUsername(m)
-			if err != nil {
-				ctx.JSON(http.StatusNotFound, gin.H{
-					"status":  "error",
-					"message": "User with username " + m + " is not found.",
-				})
-				return
-			}
-
-			var isJoined bool = false
-			for _, mentor := range class.Mentors {
-				if mentor.Username == user.Username {
-					isJoined = true
-				}
-			}
-
-			if !isJoined {
-				users = append(users, &user)
-			}
-		}
-
-		models.DB().Model(&class).Association("Mentors").Append(users)
-		models.DB().Save(&class)
-	}
-
-	// add member
-	if len(add_member) > 0 {
-		isUser, err := getUserFromContext(ctx.Request)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Authentication is required.",
-			})
-			return
-		}
-
-		// check if this user is mentor for this class.
-		for _, m := range class.Mentors {
-			if m.Username != isUser.Username {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"status":  "error",
-					"message": "This method is not permitted",
-				})
-				return
-			}
-		}
-
-		var users []*models.User
-		for _, m := range add_member {
-			user, err := models.GetUserByUsername(m)
-			if err != nil {
-				ctx.JSON(http.StatusNotFound, gin.H{
-					"status":  "error",
-					"message": "User with username " + m + " is not found.",
-				})
-				return
-			}
-
-			var isJoined bool = false
-			for _, member := range class.Members {
-				if member.Username == user.Username {
-					isJoined = true
-				}
-			}
-
-			if !isJoined {
-				users = append(users, &user)
-			}
-		}
-
-		models.DB().Model(&class).Association("Members").Append(users)
-	}
-
-	// remove mentor
-	if len(delete_mentor) > 0 {
-		isUser, err := getUserFromContext(ctx.Request)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Authentication is required.",
-			})
-			return
-		}
-
-		// check if this user is mentor for this class.
-		for _, m := range class.Mentors {
-			if m.Username != isUser.Username {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"status":  "error",
-					"message": "This method is not permitted",
-				})
-				return
-			}
-		}
-
-		var users []*models.User
-		for _, m := range delete_mentor {
-			user, err := models.GetUserByUsername(m)
-			if err != nil {
-				ctx.JSON(http.StatusNotFound, gin.H{
-					"status":  "error",
-					"message": "User with username " + m + " is not found.",
-				})
-				return
-			}
-
-			var isJoined bool = false
-			for _, mentor := range class.Mentors {
-				if mentor.Username == user.Username {
-					isJoined = true
-				}
-			}
-
-			if isJoined {
-				users = append(users, &user)
-			}
-		}
-
-		models.DB().Model(&class).Association("Mentors").Delete(users)
-	}
-
-	// remove member
-	if len(delete_member) > 0 {
-		isUser, err := getUserFromContext(ctx.Request)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Authentication is required.",
-			})
-			return
-		}
-
-		// check if this user is mentor for this class.
-		for _, m := range class.Mentors {
-			if m.Username != isUser.Username {
-				ctx.JSON(http.StatusBadRequest, gin.H{
-					"status":  "error",
-					"message": "This method is not permitted",
-				})
-				return
-			}
-		}
-
-		var users []*models.User
-		for _, m := range delete_member {
-			user, err := models.GetUserByUsername(m)
-			if err != nil {
-				ctx.JSON(http.StatusNotFound, gin.H{
-					"status":  "error",
-					"message": "User with username " + m + " is not found.",
-				})
-				return
-			}
-
-			var isJoined bool = false
-			for _, member := range class.Members {
-				if member.Username == user.Username {
-					isJoined = true
-				}
-			}
-
-			if isJoined {
-				users = append(users, &user)
-			}
-		}
-
-		models.DB().Model(&class).Association("Members").Delete(users)
-	}
-
 	ctx.JSON(http.StatusOK, class)
 }
 
 // Update handler
 func (c ClassHandlerV1) Update(ctx *gin.Context) {
-	thisUser, err := getUserFromContext(ctx.Request)
+	db := c.db.WithContext(ctx.Request.Context())
+	thisUser, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -357,7 +203,7 @@ func (c ClassHandlerV1) Update(ctx *gin.Context) {
 	}
 
 	slugClass := ctx.Param("slug")
-	class, err := models.GetClassBySlug(slugClass)
+	class, err := models.NewClassModel(db).GetClassBySlug(slugClass)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
@@ -366,16 +212,8 @@ func (c ClassHandlerV1) Update(ctx *gin.Context) {
 		return
 	}
 
-	// check this user what is mentor of this class.
-	var isMentor bool = false
-	for _, mentor := range class.Mentors {
-		if mentor.Username == thisUser.Username {
-			isMentor = true
-		}
-	}
-
-	if !isMentor || thisUser.Type == models.MEMBER {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+	if !policy.Can(thisUser, policy.UpdateClass, class) {
+		ctx.JSON(http.StatusForbidden, gin.H{
 			"status":  "error",
 			"message": "Access danied.",
 		})
@@ -388,6 +226,11 @@ func (c ClassHandlerV1) Update(ctx *gin.Context) {
 		Description string                `form:"description"`
 		Logo        *multipart.FileHeader `form:"logo"`
 		IsActive    bool                  `form:"is_active"`
+		Owner       string                `form:"owner"`
+		Capacity    *int                  `form:"capacity"`
+		// empty value remove the enrollment open or close time.
+		EnrollmentOpensAt  *time.Time `form:"enrollment_opens_at"`
+		EnrollmentClosesAt *time.Time `form:"enrollment_closes_at"`
 	}{}
 	err = ctx.ShouldBindWith(&payloads, binding.FormMultipart)
 	if err != nil {
@@ -398,61 +241,110 @@ func (c ClassHandlerV1) Update(ctx *gin.Context) {
 		return
 	}
 
-	if payloads.Title != "" {
-		class.Title = payloads.Title
-		class.Slug = slug.MakeLang(payloads.Title, "id")
-		// move file
-		s := strings.Split(*class.Logo, "/")
-		oldFilename := s[3]
-
-		oldDestination := class.Logo
-		newDestination := fmt.Sprintf("media/classes/%s/%s", class.Slug, oldFilename)
-
-		// read old file
-		sourceFile, err := os.Open(*oldDestination)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
+	if payloads.Capacity != nil {
+		if *payloads.Capacity < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
-				"message": err.Error(),
+				"message": "Capacity must not be negative, 0 is unlimited.",
 			})
 			return
 		}
-		defer sourceFile.Close()
+		class.Capacity = *payloads.Capacity
+	}
+	if payloads.EnrollmentOpensAt != nil {
+		class.EnrollmentOpensAt = nilIfZero(payloads.EnrollmentOpensAt)
+	}
+	if payloads.EnrollmentClosesAt != nil {
+		class.EnrollmentClosesAt = nilIfZero(payloads.EnrollmentClosesAt)
+	}
+	if !isValidEnrollmentWindow(class) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Enrollment close time must be after the open time.",
+		})
+		return
+	}
 
-		os.MkdirAll(fmt.Sprintf("media/classes/%s", class.Slug), 0700)
-		// make new file
-		newFile, err := os.Create(newDestination)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
+	// transfer class to another mentor of this class.
+	if payloads.Owner != "" {
+		if !policy.Can(thisUser, policy.TransferClass, class) {
+			ctx.JSON(http.StatusForbidden, gin.H{
 				"status":  "error",
-				"message": err.Error(),
+				"message": "Only owner of class can transfer the class.",
 			})
 			return
 		}
-		defer newFile.Close()
 
-		// copy file to source destination
-		_, err = io.Copy(newFile, sourceFile)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
+		owner, err := models.NewUserModel(db).GetUserByUsername(payloads.Owner)
+		if err != nil || !class.IsMentor(owner.ID) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
-				"message": err.Error(),
+				"message": "User with username " + payloads.Owner + " is not mentor of this class.",
 			})
 			return
 		}
+		class.OwnerID = &owner.ID
+	}
 
-		class.Logo = &newDestination
-		models.DB().Save(&class)
+	if payloads.Title != "" {
+		class.Title = payloads.Title
+		class.Slug = slug.MakeLang(payloads.Title, "id")
+		// move logo to directory of the new slug, class created by seed
+		// command has no logo.
+		if class.Logo != nil {
+			oldFilename := filepath.Base(*class.Logo)
+
+			oldDestination := class.Logo
+			newDestination := fmt.Sprintf("media/classes/%s/%s", class.Slug, oldFilename)
+
+			// read old file
+			sourceFile, err := os.Open(*oldDestination)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"status":  "error",
+					"message": err.Error(),
+				})
+				return
+			}
+			defer sourceFile.Close()
+
+			os.MkdirAll(fmt.Sprintf("media/classes/%s", class.Slug), 0700)
+			// make new file
+			newFile, err := os.Create(newDestination)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"status":  "error",
+					"message": err.Error(),
+				})
+				return
+			}
+			defer newFile.Close()
+
+			// copy file to source destination
+			_, err = io.Copy(newFile, sourceFile)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"status":  "error",
+					"message": err.Error(),
+				})
+				return
+			}
+
+			class.Logo = &newDestination
+		}
+		db.Save(&class)
 	}
 
 	if payloads.Description != "" {
 		class.Description = payloads.Description
-		models.DB().Save(&class)
+		db.Save(&class)
 	}
 
 	if payloads.Logo != nil {
 		// remove old file
-		os.RemoveAll(*class.Logo)
+		if class.Logo != nil {
+			os.RemoveAll(*class.Logo)
+		}
 
 		if !isAllowedExtension(filepath.Ext(payloads.Logo.Filename)) {
 			ctx.JSON(http.StatusBadRequest, gin.H{
@@ -473,28 +365,33 @@ func (c ClassHandlerV1) Update(ctx *gin.Context) {
 		}
 
 		class.Logo = &newDestination
-		models.DB().Save(&class)
+		db.Save(&class)
 	}
 
 	class.IsActive = payloads.IsActive
-	models.DB().Save(&class)
+	db.Save(&class)
+
+	// new seats are given to the waiting users.
+	if payloads.Capacity != nil {
+		promoted, err := models.NewClassModel(db).FillSeats(&class)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.notifyPromoted(db, &class, promoted)
+	}
 
 	ctx.JSON(http.StatusOK, class)
 }
 
 // Delete handler
 func (c ClassHandlerV1) Delete(ctx *gin.Context) {
-	thisUser, err := getUserFromContext(ctx.Request)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Authentication is required.",
-		})
-		return
-	}
-
+	db := c.db.WithContext(ctx.Request.Context())
 	slugClass := ctx.Param("slug")
-	class, err := models.GetClassBySlug(slugClass)
+	class, err := models.NewClassModel(db).GetClassBySlug(slugClass)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
@@ -503,15 +400,22 @@ func (c ClassHandlerV1) Delete(ctx *gin.Context) {
 		return
 	}
 
-	// only admin can delete
-	if thisUser.Type != models.ADMIN {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Permission danied",
-		})
-		return
+	db.Delete(&class)
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// nilIfZero return nil for empty time from form.
+func nilIfZero(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
 	}
+	return t
+}
 
-	models.DB().Delete(&class)
-	ctx.JSON(http.StatusNoContent, nil)
+// close time of enrollment must be after the open time.
+func isValidEnrollmentWindow(class models.Class) bool {
+	if class.EnrollmentOpensAt == nil || class.EnrollmentClosesAt == nil {
+		return true
+	}
+	return class.EnrollmentClosesAt.After(*class.EnrollmentOpensAt)
 }
@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+)
+
+func TestCreateClassPermission(t *testing.T) {
+	r, db := newServer(t)
+	tests := []struct {
+		userType models.UserType
+		want     int
+	}{
+		// admin pass the permission check and fail on the empty payload.
+		{models.ADMIN, http.StatusBadRequest},
+		{models.MENTOR, http.StatusForbidden},
+		{models.EDITOR, http.StatusForbidden},
+		{models.MEMBER, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userType.String(), func(t *testing.T) {
+			createUser(t, db, tt.userType.String(), tt.userType)
+			token := login(t, r, tt.userType.String())
+			code, body := request(t, r, http.MethodPost, "/v1/classes", token, nil)
+			if code != tt.want {
+				t.Fatalf("got %d %v, want %d", code, body, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateClassWithoutLogo(t *testing.T) {
+	r, db := newServer(t)
+	mentor := createUser(t, db, "mentor", models.MENTOR)
+	// class of seed command has no logo.
+	createClass(t, db, mentor)
+	token := login(t, r, "mentor")
+
+	var payload bytes.Buffer
+	form := multipart.NewWriter(&payload)
+	form.WriteField("title", "Golang Lanjutan")
+	form.WriteField("is_active", "true")
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/classes/golang-dasar", &payload)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d %s", w.Code, w.Body.String())
+	}
+
+	var class models.Class
+	if err := json.Unmarshal(w.Body.Bytes(), &class); err != nil {
+		t.Fatal(err)
+	}
+	if class.Slug != "golang-lanjutan" || class.Logo != nil {
+		t.Fatalf("got slug %q and logo %v, want golang-lanjutan without logo", class.Slug, class.Logo)
+	}
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxUploadFiles is maximum number of files in one upload.
+const maxUploadFiles = 20
+
+// load class of request and check that this user is mentor of the class.
+func classForGallery(db *gorm.DB, ctx *gin.Context) (models.Class, bool) {
+	class, _, ok := loadClassFor(db, ctx, "Only mentor of this class can manage images.", policy.ManageClassImages)
+	return class, ok
+}
+
+// load image of class in request.
+func imageForGallery(db *gorm.DB, ctx *gin.Context, class models.Class) (models.ClassImage, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Image not found.",
+		})
+		return models.ClassImage{}, false
+	}
+
+	image, err := models.NewClassImageModel(db).GetImage(class.ID, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Image not found.",
+		})
+		return image, false
+	}
+	return image, true
+}
+
+// removeClassFile remove uploaded file of class, file outside media/classes
+// is never removed.
+func removeClassFile(path string) {
+	if !strings.HasPrefix(filepath.Clean(path), filepath.Join("media", "classes")+string(filepath.Separator)) {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("remove image: %v", err)
+	}
+}
+
+// ImagesHandler is handler to list images of class gallery in order.
+func (c ClassHandlerV1) ImagesHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"images": class.Images,
+	})
+}
+
+// UploadImagesHandler is handler to add images to class gallery, the files
+// are sent in "images" field of multipart form and their captions in
+// "captions" field with the same order.
+func (c ClassHandlerV1) UploadImagesHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForGallery(db, ctx)
+	if !ok {
+		return
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload type is not multipart/form-data",
+		})
+		return
+	}
+	files := form.File["images"]
+	captions := form.Value["captions"]
+	if len(files) == 0 || len(files) > maxUploadFiles {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("Please upload 1 to %d images.", maxUploadFiles),
+		})
+		return
+	}
+	if len(captions) > len(files) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "There are more captions than images.",
+		})
+		return
+	}
+
+	// check every file before saving any of them.
+	for _, file := range files {
+		if !isAllowedExtension(strings.ToLower(filepath.Ext(file.Filename))) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Please upload image only, " + file.Filename + " is not an image.",
+			})
+			return
+		}
+	}
+	for _, caption := range captions {
+		if len(caption) > 255 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Caption must not be longer than 255 characters.",
+			})
+			return
+		}
+	}
+
+	images := make([]models.ClassImage, 0, len(files))
+	for i, file := range files {
+		filename := uuid.NewString() + strings.ToLower(filepath.Ext(file.Filename))
+		destination := fmt.Sprintf("media/classes/%s/%s", class.Slug, filename)
+		err := ctx.SaveUploadedFile(file, destination)
+		if err != nil {
+			for _, image := range images {
+				removeClassFile(image.Image)
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		image := models.ClassImage{Image: destination}
+		if i < len(captions) && strings.TrimSpace(captions[i]) != "" {
+			caption := strings.TrimSpace(captions[i])
+			image.Caption = &caption
+		}
+		images = append(images, image)
+	}
+
+	err = models.NewClassImageModel(db).CreateImages(class.ID, images)
+	if err != nil {
+		for _, image := range images {
+			removeClassFile(image.Image)
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status": "success",
+		"images": images,
+	})
+}
+
+// UpdateImageHandler is handler to change caption of image, empty caption
+// remove it.
+func (c ClassHandlerV1) UpdateImageHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForGallery(db, ctx)
+	if !ok {
+		return
+	}
+	image, ok := imageForGallery(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Caption string `json:"caption" validate:"max=255"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	if !validatePayloads(ctx, &payloads) {
+		return
+	}
+
+	var caption *string
+	if value := strings.TrimSpace(payloads.Caption); value != "" {
+		caption = &value
+	}
+	err = models.NewClassImageModel(db).UpdateCaption(&image, caption)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"image":  image,
+	})
+}
+
+// ReorderImagesHandler is handler to set order of class gallery, every image
+// of the class must be given by its id.
+func (c ClassHandlerV1) ReorderImagesHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForGallery(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Images []int `json:"images"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	existing := make([]int, len(class.Images))
+	for i, image := range class.Images {
+		existing[i] = image.ID
+	}
+	if !reorderIDs(ctx, "Image", existing, payloads.Images) {
+		return
+	}
+
+	imageModel := models.NewClassImageModel(db)
+	err = imageModel.Reorder(class.ID, payloads.Images)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	images, _ := imageModel.GetClassImages(class.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"images": images,
+	})
+}
+
+// DeleteImageHandler is handler to delete image from class gallery with its
+// file.
+func (c ClassHandlerV1) DeleteImageHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := classForGallery(db, ctx)
+	if !ok {
+		return
+	}
+	image, ok := imageForGallery(db, ctx, class)
+	if !ok {
+		return
+	}
+
+	err := models.NewClassImageModel(db).DeleteImage(image)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	removeClassFile(image.Image)
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/Aeroxee/kafekoding-api/recurrence"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gosimple/slug"
+	"gorm.io/gorm"
+)
+
+// load class of request and check that this user is staff of the class.
+func (c ClassHandlerV1) classForMeeting(db *gorm.DB, ctx *gin.Context) (models.Class, bool) {
+	class, _, ok := loadClassFor(db, ctx, "Only mentor of this class can manage meetings.", policy.ManageMeetings)
+	return class, ok
+}
+
+// classMeeting find meeting of class to link with lesson or assignment,
+// empty slug remove the link.
+func classMeeting(db *gorm.DB, ctx *gin.Context, class models.Class, meetingSlug string) (*int, bool) {
+	if meetingSlug == "" {
+		return nil, true
+	}
+	meeting, err := models.NewClassMeetingModel(db).GetMeetingBySlug(class.ID, meetingSlug)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Meeting " + meetingSlug + " is not found in this class.",
+		})
+		return nil, false
+	}
+	return &meeting.ID, true
+}
+
+// MeetingsHandler is handler to list meetings of class in order.
+func (c ClassHandlerV1) MeetingsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	meetings, err := models.NewClassMeetingModel(db).GetClassMeetings(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"meetings": meetings,
+	})
+}
+
+// MeetingDetailHandler is handler to get meeting of class.
+func (c ClassHandlerV1) MeetingDetailHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	meeting, err := models.NewClassMeetingModel(db).GetMeetingBySlug(class.ID, ctx.Param("meeting"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Meeting not found.",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"meeting": meeting,
+	})
+}
+
+// CreateMeetingHandler is handler for mentor to create meeting at the end of
+// class meetings.
+func (c ClassHandlerV1) CreateMeetingHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title    string    `json:"title" validate:"required,max=50"`
+		Content  string    `json:"content"`
+		OpenedAt time.Time `json:"opened_at" validate:"required"`
+		ClosedAt time.Time `json:"closed_at" validate:"required,gtfield=OpenedAt"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		var errorMessages []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessages = append(errorMessages, fmt.Sprintf("Error on field: %s with %s.", err.Field(), err.ActualTag()))
+		}
+
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":   "error",
+			"message":  "Validation error, closed_at must be after opened_at.",
+			"messages": errorMessages,
+		})
+		return
+	}
+
+	meetingModel := models.NewClassMeetingModel(db)
+	meetingSlug, err := meetingModel.UniqueSlug(class.ID, slug.MakeLang(payloads.Title, "id"), 0)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	meeting := models.ClassMeeting{
+		ClassID:  class.ID,
+		Title:    payloads.Title,
+		Slug:     meetingSlug,
+		Content:  payloads.Content,
+		OpenedAt: payloads.OpenedAt,
+		ClosedAt: payloads.ClosedAt,
+	}
+	err = meetingModel.CreateMeeting(&meeting)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"meeting": meeting,
+	})
+}
+
+// UpdateMeetingHandler is handler for mentor to edit meeting, only the given
+// fields are changed.
+func (c ClassHandlerV1) UpdateMeetingHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	meetingModel := models.NewClassMeetingModel(db)
+	meeting, err := meetingModel.GetMeetingBySlug(class.ID, ctx.Param("meeting"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Meeting not found.",
+		})
+		return
+	}
+
+	payloads := struct {
+		Title    *string    `json:"title"`
+		Content  *string    `json:"content"`
+		OpenedAt *time.Time `json:"opened_at"`
+		ClosedAt *time.Time `json:"closed_at"`
+	}{}
+	err = ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	if payloads.Title != nil && *payloads.Title != meeting.Title {
+		if *payloads.Title == "" || len(*payloads.Title) > 50 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Title is required, maximum 50 characters.",
+			})
+			return
+		}
+
+		meeting.Title = *payloads.Title
+		meeting.Slug, err = meetingModel.UniqueSlug(class.ID, slug.MakeLang(meeting.Title, "id"), meeting.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+	if payloads.Content != nil {
+		meeting.Content = *payloads.Content
+	}
+	if payloads.OpenedAt != nil {
+		meeting.OpenedAt = *payloads.OpenedAt
+	}
+	if payloads.ClosedAt != nil {
+		meeting.ClosedAt = *payloads.ClosedAt
+	}
+	if !meeting.ClosedAt.After(meeting.OpenedAt) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "closed_at must be after opened_at.",
+		})
+		return
+	}
+
+	err = db.Save(&meeting).Error
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"meeting": meeting,
+	})
+}
+
+// DeleteMeetingHandler is handler for mentor to delete meeting.
+func (c ClassHandlerV1) DeleteMeetingHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	meeting, err := models.NewClassMeetingModel(db).GetMeetingBySlug(class.ID, ctx.Param("meeting"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Meeting not found.",
+		})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// the schedule skip the date of deleted meeting.
+		if meeting.ScheduleID != nil && meeting.OccurrenceAt != nil {
+			scheduleModel := models.NewClassMeetingScheduleModel(tx)
+			schedule, err := scheduleModel.GetSchedule(class.ID, *meeting.ScheduleID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err == nil {
+				loc, err := schedule.Location()
+				if err != nil {
+					return err
+				}
+				err = scheduleModel.AddException(&schedule, meeting.OccurrenceAt.In(loc).Format(recurrence.DateLayout))
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Delete(&meeting).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// ReorderMeetingsHandler is handler for mentor to change order of meetings,
+// the payload is slugs of all meetings of the class in the new order.
+func (c ClassHandlerV1) ReorderMeetingsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Meetings []string `json:"meetings"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	meetingModel := models.NewClassMeetingModel(db)
+	meetings, err := meetingModel.GetClassMeetings(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	existing := make([]string, len(meetings))
+	ids := make(map[string]int, len(meetings))
+	for i, meeting := range meetings {
+		existing[i] = meeting.Slug
+		ids[meeting.Slug] = meeting.ID
+	}
+	if !reorderIDs(ctx, "Meeting", existing, payloads.Meetings) {
+		return
+	}
+	order := make([]int, len(payloads.Meetings))
+	for i, meetingSlug := range payloads.Meetings {
+		order[i] = ids[meetingSlug]
+	}
+
+	err = meetingModel.Reorder(class.ID, order)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	meetings, _ = meetingModel.GetClassMeetings(class.ID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"meetings": meetings,
+	})
+}
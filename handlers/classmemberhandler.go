@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// classRole is role of user in class that is managed by membership handlers.
+type classRole struct {
+	name   string
+	action policy.Action
+}
+
+var (
+	memberRole = classRole{name: "member", action: policy.ManageClassMembers}
+	mentorRole = classRole{name: "mentor", action: policy.ManageClassMentors}
+)
+
+// membershipResult is result of adding or removing one user in bulk request.
+type membershipResult struct {
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	Code     int    `json:"code"`
+	Message  string `json:"message"`
+}
+
+// AddMemberHandler is handler to add user as member of class.
+func (c ClassHandlerV1) AddMemberHandler(ctx *gin.Context) {
+	c.membershipHandler(ctx, memberRole, true)
+}
+
+// RemoveMemberHandler is handler to remove user from member of class.
+func (c ClassHandlerV1) RemoveMemberHandler(ctx *gin.Context) {
+	c.membershipHandler(ctx, memberRole, false)
+}
+
+// AddMentorHandler is handler to add user as mentor of class.
+func (c ClassHandlerV1) AddMentorHandler(ctx *gin.Context) {
+	c.membershipHandler(ctx, mentorRole, true)
+}
+
+// RemoveMentorHandler is handler to remove user from mentor of class.
+func (c ClassHandlerV1) RemoveMentorHandler(ctx *gin.Context) {
+	c.membershipHandler(ctx, mentorRole, false)
+}
+
+// BulkAddMembersHandler is handler to add many users as member of class.
+func (c ClassHandlerV1) BulkAddMembersHandler(ctx *gin.Context) {
+	c.bulkMembershipHandler(ctx, memberRole, true)
+}
+
+// BulkRemoveMembersHandler is handler to remove many users from member of class.
+func (c ClassHandlerV1) BulkRemoveMembersHandler(ctx *gin.Context) {
+	c.bulkMembershipHandler(ctx, memberRole, false)
+}
+
+// BulkAddMentorsHandler is handler to add many users as mentor of class.
+func (c ClassHandlerV1) BulkAddMentorsHandler(ctx *gin.Context) {
+	c.bulkMembershipHandler(ctx, mentorRole, true)
+}
+
+// BulkRemoveMentorsHandler is handler to remove many users from mentor of class.
+func (c ClassHandlerV1) BulkRemoveMentorsHandler(ctx *gin.Context) {
+	c.bulkMembershipHandler(ctx, mentorRole, false)
+}
+
+// load class of request and check that this user can manage the role.
+func (c ClassHandlerV1) classForMembership(db *gorm.DB, ctx *gin.Context, role classRole) (models.Class, bool) {
+	message := fmt.Sprintf("You don't have permission to manage %s of this class.", role.name)
+	class, _, ok := loadClassFor(db, ctx, message, role.action)
+	return class, ok
+}
+
+func (c ClassHandlerV1) membershipHandler(ctx *gin.Context, role classRole, add bool) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMembership(db, ctx, role)
+	if !ok {
+		return
+	}
+
+	code, message := c.changeMembership(db, &class, role, ctx.Param("username"), add)
+	if code >= http.StatusBadRequest {
+		ctx.JSON(code, gin.H{
+			"status":  "error",
+			"message": message,
+		})
+		return
+	}
+
+	ctx.JSON(code, gin.H{
+		"status":  "success",
+		"message": message,
+	})
+}
+
+func (c ClassHandlerV1) bulkMembershipHandler(ctx *gin.Context, role classRole, add bool) {
+	db := c.db.WithContext(ctx.Request.Context())
+	payloads := struct {
+		Usernames []string `json:"usernames" validate:"required,min=1,max=100"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Usernames is required, maximum 100 usernames.",
+		})
+		return
+	}
+
+	class, ok := c.classForMembership(db, ctx, role)
+	if !ok {
+		return
+	}
+
+	results := make([]membershipResult, 0, len(payloads.Usernames))
+	seen := make(map[string]bool, len(payloads.Usernames))
+	for _, username := range payloads.Usernames {
+		// the same username is changed once, class is not reloaded between
+		// the changes.
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		code, message := c.changeMembership(db, &class, role, username, add)
+		result := membershipResult{
+			Username: username,
+			Status:   "success",
+			Code:     code,
+			Message:  message,
+		}
+		if code >= http.StatusBadRequest {
+			result.Status = "error"
+		}
+		results = append(results, result)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"results": results,
+	})
+}
+
+// changeMembership add or remove one user and return the http status and
+// message of the result.
+func (c ClassHandlerV1) changeMembership(db *gorm.DB, class *models.Class, role classRole, username string, add bool) (int, string) {
+	user, err := models.NewUserModel(db).GetUserByUsername(username)
+	if err != nil {
+		return http.StatusNotFound, "User with username " + username + " is not found."
+	}
+
+	classModel := models.NewClassModel(db)
+	isJoined := class.IsMember(user.ID)
+	if role == mentorRole {
+		isJoined = class.IsMentor(user.ID)
+	}
+
+	position, err := models.NewClassWaitlistModel(db).Position(class.ID, user.ID)
+	if err != nil {
+		return http.StatusInternalServerError, err.Error()
+	}
+
+	switch {
+	case add && isJoined:
+		return http.StatusConflict, fmt.Sprintf("User with username %s is already %s of this class.", username, role.name)
+	case !add && !isJoined:
+		return http.StatusNotFound, fmt.Sprintf("User with username %s is not %s of this class.", username, role.name)
+	case add && role == memberRole && position > 0:
+		return http.StatusConflict, fmt.Sprintf("User with username %s is already in waitlist of this class.", username)
+	case add && role == mentorRole && !policy.CanMentor(user):
+		return http.StatusBadRequest, "User with username " + username + " is not a mentor."
+	case !add && role == mentorRole && class.IsOwner(user.ID):
+		return http.StatusConflict, "Owner of class can't be removed, transfer the class first."
+	}
+
+	var waitlisted bool
+	var promoted []models.User
+	switch {
+	case add && role == mentorRole:
+		err = classModel.AddMentor(class, &user)
+	case add:
+		waitlisted, err = classModel.Enroll(class, &user)
+	case role == mentorRole:
+		err = classModel.RemoveMentor(class, &user)
+	default:
+		promoted, err = classModel.Leave(class, &user)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err.Error()
+	}
+	c.notifyPromoted(db, class, promoted)
+
+	if waitlisted {
+		return http.StatusAccepted, fmt.Sprintf("Class is full, user with username %s is added to waitlist.", username)
+	}
+
+	if add {
+		return http.StatusCreated, fmt.Sprintf("User with username %s is added as %s.", username, role.name)
+	}
+	return http.StatusOK, fmt.Sprintf("User with username %s is removed from %s.", username, role.name)
+}
@@ -0,0 +1,40 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+)
+
+func TestBulkMembersDuplicateUsernames(t *testing.T) {
+	r, db := newServer(t)
+	mentor := createUser(t, db, "mentor", models.MENTOR)
+	createUser(t, db, "budi", models.MEMBER)
+	createClass(t, db, mentor)
+	token := login(t, r, "mentor")
+
+	tests := []struct {
+		name   string
+		method string
+	}{
+		{"add", http.MethodPost},
+		{"remove", http.MethodDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := map[string][]string{"usernames": {"budi", "budi"}}
+			code, body := request(t, r, tt.method, "/v1/classes/golang-dasar/members", token, payload)
+			if code != http.StatusOK {
+				t.Fatalf("got %d %v", code, body)
+			}
+			results := body["results"].([]any)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1: %v", len(results), results)
+			}
+			if status := results[0].(map[string]any)["status"]; status != "success" {
+				t.Errorf("got %v, want success", results[0])
+			}
+		})
+	}
+}
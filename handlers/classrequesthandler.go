@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errRequestReviewed is returned when request is already approved or rejected.
+var errRequestReviewed = errors.New("request is already reviewed")
+
+// SubmitRequestHandler is handler for user to request to join or leave class.
+func (c ClassHandlerV1) SubmitRequestHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	thisUser, err := getUserFromContext(db, ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Authentication is required.",
+		})
+		return
+	}
+
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	payloads := struct {
+		Type    models.ClassPermissionType `json:"type" validate:"required,oneof=JOIN OUT"`
+		Message string                     `json:"message" validate:"max=255"`
+	}{}
+	err = ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Type must be JOIN or OUT and message is maximum 255 characters.",
+		})
+		return
+	}
+
+	if payloads.Type == models.JOIN && !class.IsActive {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Class is not active.",
+		})
+		return
+	}
+	if payloads.Type == models.JOIN && !class.IsEnrollmentOpen(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Enrollment of this class is closed.",
+		})
+		return
+	}
+	if payloads.Type == models.JOIN && class.IsMember(thisUser.ID) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "You are already member of this class.",
+		})
+		return
+	}
+	if payloads.Type == models.OUT && !class.IsMember(thisUser.ID) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "You are not member of this class.",
+		})
+		return
+	}
+
+	if payloads.Type == models.JOIN {
+		position, err := models.NewClassWaitlistModel(db).Position(class.ID, thisUser.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+		if position > 0 {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": fmt.Sprintf("You are already in waitlist of this class at position %d.", position),
+			})
+			return
+		}
+	}
+
+	permissionModel := models.NewClassPermissionModel(db)
+	pending, err := permissionModel.HasPending(class.ID, thisUser.ID, payloads.Type)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	if pending {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "You already have pending request for this class.",
+		})
+		return
+	}
+
+	request := models.ClassPermission{
+		UserID:  thisUser.ID,
+		ClassID: class.ID,
+		Type:    payloads.Type,
+		Message: payloads.Message,
+	}
+	err = permissionModel.CreateRequest(&request)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// mentors review the request, admin when class has no mentor.
+	var staff []models.User
+	for _, mentor := range class.Mentors {
+		staff = append(staff, *mentor)
+	}
+	if len(staff) == 0 {
+		staff, _ = models.NewUserModel(db).GetUsersByType(models.ADMIN)
+	}
+	action := "join"
+	if request.Type == models.OUT {
+		action = "leave"
+	}
+	message := fmt.Sprintf("%s want to %s class %s.", thisUser.Username, action, class.Title)
+	if request.Message != "" {
+		message += "\n\n" + request.Message
+	}
+	notify(db, c.mailer, staff, "New request to "+action+" "+class.Title, message)
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Request is submitted, wait for mentor to review it.",
+		"request": request,
+	})
+}
+
+// RequestsHandler is handler for mentor to list join and leave requests of
+// class, filter the status with ?status=PENDING.
+func (c ClassHandlerV1) RequestsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMembership(db, ctx, memberRole)
+	if !ok {
+		return
+	}
+
+	status := models.ClassPermissionStatus(getQueryString(ctx.Request, "status", ""))
+	requests, err := models.NewClassPermissionModel(db).GetClassRequests(class.ID, status)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"requests": requests,
+	})
+}
+
+// ApproveRequestHandler is handler for mentor to approve request, the user is
+// added to or removed from member of class.
+func (c ClassHandlerV1) ApproveRequestHandler(ctx *gin.Context) {
+	c.reviewRequestHandler(ctx, models.APPROVED)
+}
+
+// RejectRequestHandler is handler for mentor to reject request.
+func (c ClassHandlerV1) RejectRequestHandler(ctx *gin.Context) {
+	c.reviewRequestHandler(ctx, models.REJECTED)
+}
+
+func (c ClassHandlerV1) reviewRequestHandler(ctx *gin.Context, status models.ClassPermissionStatus) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMembership(db, ctx, memberRole)
+	if !ok {
+		return
+	}
+	reviewerID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	payloads := struct {
+		Note string `json:"note" validate:"max=255"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Note is maximum 255 characters.",
+		})
+		return
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	request, err := models.NewClassPermissionModel(db).GetRequest(class.ID, id)
+	if err != nil || request.User == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Request not found.",
+		})
+		return
+	}
+
+	var waitlisted bool
+	var promoted []models.User
+	err = db.Transaction(func(tx *gorm.DB) error {
+		reviewed, err := models.NewClassPermissionModel(tx).Review(&request, reviewerID, status, payloads.Note)
+		if err != nil {
+			return err
+		}
+		if !reviewed {
+			return errRequestReviewed
+		}
+		if status != models.APPROVED {
+			return nil
+		}
+
+		classModel := models.NewClassModel(tx)
+		switch {
+		case request.Type == models.JOIN && !class.IsMember(request.UserID):
+			waitlisted, err = classModel.Enroll(&class, request.User)
+		case request.Type == models.OUT && class.IsMember(request.UserID):
+			promoted, err = classModel.Leave(&class, request.User)
+		}
+		return err
+	})
+	if errors.Is(err, errRequestReviewed) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Request is already reviewed.",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	action := "join"
+	if request.Type == models.OUT {
+		action = "leave"
+	}
+	result := "approved"
+	if status == models.REJECTED {
+		result = "rejected"
+	}
+	message := fmt.Sprintf("Your request to %s class %s is %s.", action, class.Title, result)
+	if waitlisted {
+		message += " The class is full, you are added to the waitlist and become member when a seat is available."
+	}
+	if request.Note != "" {
+		message += "\n\n" + request.Note
+	}
+	notify(db, c.mailer, []models.User{*request.User}, "Request to "+action+" "+class.Title+" is "+result, message)
+	c.notifyPromoted(db, &class, promoted)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"message":    "Request is " + result + ".",
+		"request":    request,
+		"waitlisted": waitlisted,
+	})
+}
+
+// ClassRequestsHandler is handler to list join and leave requests of this user.
+func (u *UserHandlerV1) ClassRequestsHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	requests, err := models.NewClassPermissionModel(db).GetUserRequests(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"requests": requests,
+	})
+}
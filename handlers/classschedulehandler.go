@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/recurrence"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// parseLocalTime parse time in RFC3339 or local time without offset in the
+// given location, e.g. "2026-11-02T19:00".
+func parseLocalTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("time %q must be RFC3339 or YYYY-MM-DDTHH:MM", value)
+}
+
+// SchedulesHandler is handler to list recurring schedules of class.
+func (c ClassHandlerV1) SchedulesHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	schedules, err := models.NewClassMeetingScheduleModel(db).GetClassSchedules(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"schedules": schedules,
+	})
+}
+
+// CreateScheduleHandler is handler for mentor to create recurring schedule,
+// the meetings of schedule are created at once.
+func (c ClassHandlerV1) CreateScheduleHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	payloads := struct {
+		Title           string   `json:"title" validate:"required,max=40"`
+		Content         string   `json:"content"`
+		TimeZone        string   `json:"time_zone" validate:"required"`
+		StartsAt        string   `json:"starts_at" validate:"required"`
+		DurationMinutes int      `json:"duration_minutes" validate:"required,min=1,max=1440"`
+		Rule            string   `json:"rule" validate:"required"`
+		Exceptions      []string `json:"exceptions" validate:"dive,datetime=2006-01-02"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		var errorMessages []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessages = append(errorMessages, fmt.Sprintf("Error on field: %s with %s.", err.Field(), err.ActualTag()))
+		}
+
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":   "error",
+			"message":  "Validation error",
+			"messages": errorMessages,
+		})
+		return
+	}
+
+	loc, err := time.LoadLocation(payloads.TimeZone)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Time zone " + payloads.TimeZone + " is not valid, use IANA name e.g. Asia/Jakarta.",
+		})
+		return
+	}
+	startsAt, err := parseLocalTime(payloads.StartsAt, loc)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	rule, err := recurrence.Parse(payloads.Rule)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	schedule := models.ClassMeetingSchedule{
+		ClassID:         class.ID,
+		Title:           payloads.Title,
+		Content:         payloads.Content,
+		TimeZone:        loc.String(),
+		StartsAt:        startsAt.UTC(),
+		DurationMinutes: payloads.DurationMinutes,
+		Rule:            rule.String(),
+		Exceptions:      payloads.Exceptions,
+	}
+	meetings, err := models.NewClassMeetingScheduleModel(db).CreateSchedule(&schedule)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"status":   "success",
+		"schedule": schedule,
+		"meetings": meetings,
+	})
+}
+
+// DeleteScheduleHandler is handler for mentor to delete schedule and its
+// meetings that is not started yet.
+func (c ClassHandlerV1) DeleteScheduleHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	scheduleModel := models.NewClassMeetingScheduleModel(db)
+	schedule, err := scheduleModel.GetSchedule(class.ID, id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Schedule not found.",
+		})
+		return
+	}
+
+	err = scheduleModel.DeleteSchedule(schedule)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// UpdateFollowingMeetingsHandler is handler for mentor to edit a meeting of
+// schedule and all the following meetings, e.g. move the class to 20:00
+// from this week. Start time is local time in time zone of the schedule.
+func (c ClassHandlerV1) UpdateFollowingMeetingsHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMeeting(db, ctx)
+	if !ok {
+		return
+	}
+
+	meeting, err := models.NewClassMeetingModel(db).GetMeetingBySlug(class.ID, ctx.Param("meeting"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Meeting not found.",
+		})
+		return
+	}
+	if meeting.ScheduleID == nil || meeting.OccurrenceAt == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Meeting is not part of a schedule.",
+		})
+		return
+	}
+
+	scheduleModel := models.NewClassMeetingScheduleModel(db)
+	schedule, err := scheduleModel.GetSchedule(class.ID, *meeting.ScheduleID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Schedule not found.",
+		})
+		return
+	}
+
+	payloads := struct {
+		Title           *string `json:"title" validate:"omitnil,min=1,max=40"`
+		Content         *string `json:"content"`
+		StartTime       *string `json:"start_time" validate:"omitnil,datetime=15:04"`
+		DurationMinutes *int    `json:"duration_minutes" validate:"omitnil,min=1,max=1440"`
+	}{}
+	err = ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		var errorMessages []string
+		for _, err := range err.(validator.ValidationErrors) {
+			errorMessages = append(errorMessages, fmt.Sprintf("Error on field: %s with %s.", err.Field(), err.ActualTag()))
+		}
+
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":   "error",
+			"message":  "Validation error",
+			"messages": errorMessages,
+		})
+		return
+	}
+
+	change := models.ScheduleChange{
+		Title:           payloads.Title,
+		Content:         payloads.Content,
+		DurationMinutes: payloads.DurationMinutes,
+	}
+	if payloads.StartTime != nil {
+		start, _ := time.Parse("15:04", *payloads.StartTime)
+		hour, minute := start.Hour(), start.Minute()
+		change.Hour, change.Minute = &hour, &minute
+	}
+
+	schedule, err = scheduleModel.UpdateFollowing(schedule, meeting, change)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"schedule": schedule,
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// notifyPromoted is function to tell users that they are moved from waitlist
+// to member of class.
+func (c ClassHandlerV1) notifyPromoted(db *gorm.DB, class *models.Class, users []models.User) {
+	if len(users) == 0 {
+		return
+	}
+	notify(db, c.mailer, users, "You are now member of "+class.Title,
+		"A seat is available in class "+class.Title+", you are moved from the waitlist to member.")
+}
+
+// WaitlistHandler is handler for mentor to list waiting users of class.
+func (c ClassHandlerV1) WaitlistHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	class, ok := c.classForMembership(db, ctx, memberRole)
+	if !ok {
+		return
+	}
+
+	entries, err := models.NewClassWaitlistModel(db).GetClassWaitlist(class.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"capacity": class.Capacity,
+		"waitlist": entries,
+	})
+}
+
+// RemoveWaitlistHandler is handler to remove user from waitlist of class, the
+// user can remove themself.
+func (c ClassHandlerV1) RemoveWaitlistHandler(ctx *gin.Context) {
+	db := c.db.WithContext(ctx.Request.Context())
+	thisUser, err := getUserFromContext(db, ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Authentication is required.",
+		})
+		return
+	}
+
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return
+	}
+
+	username := ctx.Param("username")
+	if username != thisUser.Username && !policy.Can(thisUser, policy.ManageClassMembers, class) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "You don't have permission to manage member of this class.",
+		})
+		return
+	}
+
+	user, err := models.NewUserModel(db).GetUserByUsername(username)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "User with username " + username + " is not found.",
+		})
+		return
+	}
+
+	removed, err := models.NewClassWaitlistModel(db).Remove(class.ID, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	if !removed {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "User with username " + username + " is not in waitlist of this class.",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
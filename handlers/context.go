@@ -5,11 +5,17 @@ import (
 
 	"github.com/Aeroxee/kafekoding-api/auth"
 	"github.com/Aeroxee/kafekoding-api/models"
+	"gorm.io/gorm"
 )
 
+// get token claims from request context.
+func getClaimsFromContext(r *http.Request) auth.Claims {
+	return r.Context().Value(&auth.UserAuth{}).(auth.Claims)
+}
+
 // get user info from request context.
-func getUserFromContext(r *http.Request) (models.User, error) {
-	claims := r.Context().Value(&auth.UserAuth{}).(auth.Claims)
-	user, err := models.GetUserByID(claims.Credential.UserID)
+func getUserFromContext(db *gorm.DB, r *http.Request) (models.User, error) {
+	claims := getClaimsFromContext(r)
+	user, err := models.NewUserModel(db).GetUserByID(claims.Credential.UserID)
 	return user, err
 }
@@ -0,0 +1,170 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/Aeroxee/kafekoding-api/controllers"
+	"github.com/Aeroxee/kafekoding-api/handlers"
+	"github.com/Aeroxee/kafekoding-api/mailer"
+	"github.com/Aeroxee/kafekoding-api/middlewares"
+	"github.com/Aeroxee/kafekoding-api/migrations"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newServer open in-memory sqlite database with the full schema and route
+// user and class handlers to it.
+func newServer(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Database.Driver = config.SQLITE
+	cfg.Database.DSN = ":memory:"
+
+	db, err := models.Open(cfg.Database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { models.Close(db) })
+	if _, err := migrations.New(db).Up(0); err != nil {
+		t.Fatal(err)
+	}
+
+	mail := mailer.New(cfg.SMTP)
+	userHandler := handlers.NewUserHandlerV1(db, cfg, mail)
+	classHandlerV1 := handlers.NewClassHandlerV1(db, cfg, mail)
+
+	r := gin.New()
+	v1 := r.Group("/v1")
+	v1.POST("/get-token", userHandler.GetTokenHandler)
+
+	userGroup := v1.Group("/user")
+	userGroup.Use(middlewares.Authentication(db))
+	controllers.UserController(userGroup, userHandler)
+
+	classGroupV1WithAuth := v1.Group("/classes")
+	classGroupV1WithAuth.Use(middlewares.Authentication(db))
+	controllers.ClassControllerV1WithAuth(classGroupV1WithAuth, classHandlerV1)
+	controllers.ClassControllerV1NoAuth(v1.Group("/classes"), classHandlerV1)
+	return r, db
+}
+
+func createUser(t *testing.T, db *gorm.DB, username string, userType models.UserType) models.User {
+	t.Helper()
+	user := models.User{
+		FirstName: username,
+		LastName:  "KafeKoding",
+		Username:  username,
+		Email:     username + "@kafekoding.local",
+		Password:  "password",
+		IsActive:  true,
+		Type:      userType,
+	}
+	if err := models.NewUserModel(db).CreateNewUser(&user); err != nil {
+		t.Fatal(err)
+	}
+	// ADMIN is the zero value, it's replaced by default of the column.
+	if err := db.Model(&user).Update("type", userType).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// request send json body to the server and decode the response.
+func request(t *testing.T, r *gin.Engine, method, path, token string, body any) (int, map[string]any) {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	result := map[string]any{}
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, w.Body.String())
+		}
+	}
+	return w.Code, result
+}
+
+func login(t *testing.T, r *gin.Engine, username string) string {
+	t.Helper()
+	code, body := request(t, r, http.MethodPost, "/v1/get-token", "", map[string]string{
+		"username": username,
+		"password": "password",
+	})
+	if code != http.StatusOK {
+		t.Fatalf("login %s: %d %v", username, code, body)
+	}
+	return body["token"].(string)
+}
+
+func TestGetTokenHandler(t *testing.T) {
+	r, db := newServer(t)
+	createUser(t, db, "member", models.MEMBER)
+	inactive := createUser(t, db, "inactive", models.MEMBER)
+	db.Model(&inactive).Update("is_active", false)
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     int
+	}{
+		{"username", "member", "password", http.StatusOK},
+		{"email", "member@kafekoding.local", "password", http.StatusOK},
+		{"wrong password", "member", "wrong", http.StatusBadRequest},
+		{"unknown user", "nobody", "password", http.StatusBadRequest},
+		{"inactive user", "inactive", "password", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := request(t, r, http.MethodPost, "/v1/get-token", "", map[string]string{
+				"username": tt.username,
+				"password": tt.password,
+			})
+			if code != tt.want {
+				t.Fatalf("got %d %v, want %d", code, body, tt.want)
+			}
+			if code == http.StatusOK && body["token"] == "" {
+				t.Fatal("token is empty")
+			}
+		})
+	}
+}
+
+func TestClassDetailHandler(t *testing.T) {
+	r, db := newServer(t)
+	class := models.Class{Title: "Golang Dasar", Slug: "golang-dasar", IsActive: true}
+	if err := models.NewClassModel(db).CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+
+	code, body := request(t, r, http.MethodGet, "/v1/classes/golang-dasar", "", nil)
+	if code != http.StatusOK || body["slug"] != "golang-dasar" {
+		t.Fatalf("got %d %v", code, body)
+	}
+
+	code, _ = request(t, r, http.MethodGet, "/v1/classes/unknown", "", nil)
+	if code != http.StatusNotFound {
+		t.Fatalf("unknown class got %d, want %d", code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler is handler to publish public keys, other service use it to
+// verify token without sharing secret.
+func JWKSHandler(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, auth.GetJWKS())
+}
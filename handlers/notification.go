@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Aeroxee/kafekoding-api/mailer"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// notify is function to create notification for users and send it by email.
+// Email is sent in background so slow smtp server doesn't block the request.
+func notify(db *gorm.DB, m *mailer.Mailer, users []models.User, title, message string) {
+	notifications := make([]models.Notification, 0, len(users))
+	for _, user := range users {
+		notifications = append(notifications, models.Notification{
+			UserID:  user.ID,
+			Title:   title,
+			Message: message,
+		})
+	}
+	if err := models.NewNotificationModel(db).CreateNotifications(notifications); err != nil {
+		log.Printf("notify: %v", err)
+	}
+
+	for _, user := range users {
+		go func(email string) {
+			if err := m.Send([]string{email}, title, message); err != nil {
+				log.Printf("notify: send email to %s: %v", email, err)
+			}
+		}(user.Email)
+	}
+}
+
+// NotificationsHandler is handler to list notifications of this user, only
+// unread notifications with ?unread=true, ?size= is between 1 and 100.
+func (u *UserHandlerV1) NotificationsHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+	unread := getQueryBool(ctx.Request, "unread", false)
+	size := min(max(getQueryInt(ctx.Request, "size", 50), 1), 100)
+
+	notifications, err := models.NewNotificationModel(db).GetUserNotifications(userID, unread, size)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":        "success",
+		"notifications": notifications,
+	})
+}
+
+// ReadNotificationHandler is handler to mark notification as read, all
+// notifications of this user when id is not given.
+func (u *UserHandlerV1) ReadNotificationHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	var id int
+	if param := ctx.Param("id"); param != "" {
+		var err error
+		id, err = strconv.Atoi(param)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Notification not found.",
+			})
+			return
+		}
+	}
+
+	err := models.NewNotificationModel(db).MarkRead(userID, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// send password reset token to email target.
+func (u *UserHandlerV1) sendPasswordResetEmail(email, token string) bool {
+	subject := "Reset Your Password"
+	link := fmt.Sprintf("%s?token=%s", u.cfg.Account.PasswordResetURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Click the following link to reset your password: %s\r\n\r\n"+
+		"The link expires in %s. If you did not request a password reset, please ignore this email.",
+		link, u.cfg.Account.PasswordResetTTL.Duration)
+
+	err := u.mailer.Send([]string{email}, subject, body)
+	if err != nil {
+		log.Printf("send password reset email to %s: %v", email, err)
+		return false
+	}
+	return true
+}
+
+// ForgotPasswordHandler is handler to send password reset token to email.
+// The response is the same for unknown email, limited request and failure
+// to send, so it can't be used to check registered email. The failure is
+// only logged.
+func (u *UserHandlerV1) ForgotPasswordHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	payloads := struct {
+		Email string `json:"email" validate:"required,email"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validation error",
+		})
+		return
+	}
+
+	successMessage := gin.H{
+		"status":  "success",
+		"message": "If the email is registered, a password reset link is sent to it.",
+	}
+
+	user, err := models.NewUserModel(db).GetUserByEmail(payloads.Email)
+	if err != nil {
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+
+	userTokenModel := models.NewUserTokenModel(db)
+	message, err := u.tokenRateLimit(userTokenModel, user.ID, models.PASSWORD_RESET)
+	if err != nil {
+		log.Printf("password reset of user %d: %v", user.ID, err)
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+	if message != "" {
+		log.Printf("password reset of user %d is limited: %s", user.ID, message)
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+
+	token, err := userTokenModel.CreateToken(user.ID, models.PASSWORD_RESET, u.cfg.Account.PasswordResetTTL.Duration)
+	if err != nil {
+		log.Printf("password reset of user %d: %v", user.ID, err)
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+
+	// failure to send is logged by sendPasswordResetEmail.
+	u.sendPasswordResetEmail(user.Email, token)
+	ctx.JSON(http.StatusOK, successMessage)
+}
+
+// ResetPasswordHandler is handler to set new password with token from
+// email. All token issued to the user before is revoked.
+func (u *UserHandlerV1) ResetPasswordHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	payloads := struct {
+		Token              string `json:"token" validate:"required"`
+		NewPassword        string `json:"new_password" validate:"required"`
+		NewPasswordConfirm string `json:"new_password_confirm" validate:"required"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validation error",
+		})
+		return
+	}
+
+	if payloads.NewPassword != payloads.NewPasswordConfirm {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Password confirmation not same.",
+		})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		userToken, err := models.NewUserTokenModel(tx).UseToken(models.PASSWORD_RESET, payloads.Token)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.User{}).Where("id = ?", userToken.UserID).
+			Update("password", auth.EncryptionPassword(payloads.NewPassword)).Error
+		if err != nil {
+			return err
+		}
+
+		return models.NewUserModel(tx).RevokeTokens(userToken.UserID)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid or expired password reset token.",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Reset password is successfully, please login with your new password.",
+	})
+}
@@ -3,6 +3,11 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func getQueryString(r *http.Request, key, defaultValue string) string {
@@ -41,3 +46,36 @@ func getQueryBool(r *http.Request, key string, defaultValue bool) bool {
 
 	return resultBool
 }
+
+// loadClassFor load class of request and check that this user can do one of
+// the actions to the class, message is sent when this user can't.
+func loadClassFor(db *gorm.DB, ctx *gin.Context, message string, actions ...policy.Action) (models.Class, models.User, bool) {
+	thisUser, err := getUserFromContext(db, ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Authentication is required.",
+		})
+		return models.Class{}, thisUser, false
+	}
+
+	class, err := models.NewClassModel(db).GetClassBySlug(ctx.Param("slug"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Class not found.",
+		})
+		return class, thisUser, false
+	}
+
+	for _, action := range actions {
+		if policy.Can(thisUser, action, class) {
+			return class, thisUser, true
+		}
+	}
+	ctx.JSON(http.StatusForbidden, gin.H{
+		"status":  "error",
+		"message": message,
+	})
+	return class, thisUser, false
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SessionsHandler is handler to list active login session of this user.
+func (u *UserHandlerV1) SessionsHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	claims := getClaimsFromContext(ctx.Request)
+
+	sessions, err := models.NewSessionModel(db).GetActiveSessions(claims.Credential.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, gin.H{
+			"id":           session.ID,
+			"user_agent":   session.UserAgent,
+			"ip":           session.IP,
+			"created_at":   session.CreatedAt,
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.ID == claims.Credential.SessionID,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"sessions": result,
+	})
+}
+
+// DeleteSessionHandler is handler to revoke login session of this user,
+// e.g. login in shared computer.
+func (u *UserHandlerV1) DeleteSessionHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	sessionModel := models.NewSessionModel(db)
+	session, err := sessionModel.GetActiveSession(ctx.Param("id"))
+	if err != nil || session.UserID != userID {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Session not found.",
+		})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		sessionModel := models.NewSessionModel(tx)
+		if err := sessionModel.RevokeSession(session.ID); err != nil {
+			return err
+		}
+
+		count, err := sessionModel.CountActive(userID)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("is_logined", count > 0).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// issueTokens create access token and refresh token of session for user,
+// the session id is the family of the refresh token.
+func (u *UserHandlerV1) issueTokens(db *gorm.DB, user models.User, session models.Session) (gin.H, error) {
+	credential := auth.Credential{
+		UserID:       user.ID,
+		Username:     user.Username,
+		TokenVersion: user.TokenVersion,
+		SessionID:    session.ID,
+	}
+
+	token, err := auth.GetToken(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, issued, err := models.NewRefreshTokenModel(db).Issue(user, session.ID, u.cfg.JWT.RefreshTTL.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	// session live as long as its last refresh token.
+	if err := models.NewSessionModel(db).Extend(session.ID, issued.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"token":         token,
+		"token_type":    "Bearer",
+		"expires_in":    int(u.cfg.JWT.TTL.Seconds()),
+		"refresh_token": refreshToken,
+		"session_id":    session.ID,
+	}, nil
+}
+
+// RefreshTokenHandler is handler to get new access token with refresh
+// token. The refresh token is rotated, the old one can't be used again.
+func (u *UserHandlerV1) RefreshTokenHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	payloads := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil || payloads.RefreshToken == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	refreshTokenModel := models.NewRefreshTokenModel(db)
+	refreshToken, err := refreshTokenModel.Use(payloads.RefreshToken)
+	if errors.Is(err, models.ErrRefreshTokenInvalid) || errors.Is(err, models.ErrRefreshTokenReused) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// token version is changed when all token of user is revoked.
+	sessionModel := models.NewSessionModel(db)
+	user, err := models.NewUserModel(db).GetUserByID(refreshToken.UserID)
+	if err != nil || !user.IsActive || user.TokenVersion != refreshToken.TokenVersion {
+		sessionModel.RevokeSession(refreshToken.FamilyID)
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": models.ErrRefreshTokenInvalid.Error(),
+		})
+		return
+	}
+
+	session, err := sessionModel.GetActiveSession(refreshToken.FamilyID)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Session is revoked, please login again.",
+		})
+		return
+	}
+	sessionModel.Touch(session, ctx.Request.UserAgent(), ctx.ClientIP())
+
+	tokens, err := u.issueTokens(db, user, session)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	tokens["status"] = "success"
+	tokens["message"] = "Refresh token is successfully."
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// LogoutHandler is handler to revoke token and session of this request,
+// session of refresh token in payload is revoked too.
+func (u *UserHandlerV1) LogoutHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	payloads := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{}
+	// payload is optional.
+	ctx.ShouldBindJSON(&payloads)
+
+	claims := getClaimsFromContext(ctx.Request)
+	userID := claims.Credential.UserID
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if claims.ID != "" && claims.ExpiresAt != nil {
+			err := models.NewRevokedTokenModel(tx).Revoke(claims.ID, userID, claims.ExpiresAt.Time)
+			if err != nil {
+				return err
+			}
+		}
+
+		sessionModel := models.NewSessionModel(tx)
+		if claims.Credential.SessionID != "" {
+			if err := sessionModel.RevokeSession(claims.Credential.SessionID); err != nil {
+				return err
+			}
+		}
+
+		if payloads.RefreshToken != "" {
+			refreshToken, err := models.NewRefreshTokenModel(tx).GetActive(payloads.RefreshToken)
+			if err == nil && refreshToken.UserID == userID {
+				if err := sessionModel.RevokeSession(refreshToken.FamilyID); err != nil {
+					return err
+				}
+			}
+		}
+
+		count, err := sessionModel.CountActive(userID)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("is_logined", count > 0).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Logout is successfully.",
+	})
+}
+
+// LogoutAllHandler is handler to revoke all access and refresh token of
+// this user.
+func (u *UserHandlerV1) LogoutAllHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	userID := getClaimsFromContext(ctx.Request).Credential.UserID
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := models.NewUserModel(tx).RevokeTokens(userID); err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("is_logined", false).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Logout from all devices is successfully.",
+	})
+}
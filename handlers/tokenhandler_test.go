@@ -0,0 +1,52 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+)
+
+func TestLogoutHandler(t *testing.T) {
+	r, db := newServer(t)
+	createUser(t, db, "member", models.MEMBER)
+	token := login(t, r, "member")
+	other := login(t, r, "member")
+
+	code, body := request(t, r, http.MethodGet, "/v1/user/auth", token, nil)
+	if code != http.StatusOK || body["username"] != "member" {
+		t.Fatalf("got %d %v", code, body)
+	}
+
+	code, _ = request(t, r, http.MethodPost, "/v1/user/logout", token, nil)
+	if code != http.StatusOK {
+		t.Fatalf("logout got %d", code)
+	}
+	code, _ = request(t, r, http.MethodGet, "/v1/user/auth", token, nil)
+	if code != http.StatusUnauthorized {
+		t.Fatalf("after logout got %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	// token of the other login is still valid.
+	code, _ = request(t, r, http.MethodGet, "/v1/user/auth", other, nil)
+	if code != http.StatusOK {
+		t.Fatalf("other token got %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestLogoutAllHandler(t *testing.T) {
+	r, db := newServer(t)
+	createUser(t, db, "member", models.MEMBER)
+	tokens := []string{login(t, r, "member"), login(t, r, "member")}
+
+	code, _ := request(t, r, http.MethodPost, "/v1/user/logout-all", tokens[0], nil)
+	if code != http.StatusOK {
+		t.Fatalf("logout-all got %d", code)
+	}
+	for i, token := range tokens {
+		code, _ := request(t, r, http.MethodGet, "/v1/user/auth", token, nil)
+		if code != http.StatusUnauthorized {
+			t.Errorf("token %d got %d, want %d", i, code, http.StatusUnauthorized)
+		}
+	}
+}
@@ -5,72 +5,74 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
-	"net/smtp"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/Aeroxee/kafekoding-api/mailer"
 	"github.com/Aeroxee/kafekoding-api/models"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
-type UserHandlerV1 struct{}
-
-func NewUserHandlerV1() UserHandlerV1 {
-	return UserHandlerV1{}
+type UserHandlerV1 struct {
+	db     *gorm.DB
+	cfg    config.Config
+	mailer *mailer.Mailer
 }
 
-// validate
-var validate *validator.Validate
-
-// ActivationData struct for save activation data
-type ActivationData struct {
-	Email          string
-	ExpirationTime time.Time
+func NewUserHandlerV1(db *gorm.DB, cfg config.Config, mailer *mailer.Mailer) UserHandlerV1 {
+	return UserHandlerV1{
+		db:     db,
+		cfg:    cfg,
+		mailer: mailer,
+	}
 }
 
-// activation data
-var activationData = make(map[string]ActivationData)
-
-// generate activation code
-func (u *UserHandlerV1) generateActivationCode() string {
-	return uuid.New().String()
-}
+// validate is shared by all handler, validator is safe for concurrent use
+// and cache the struct so it's created once.
+var validate = validator.New(validator.WithRequiredStructEnabled())
 
 // send activation code to email target.
 func (u *UserHandlerV1) sendActivationEmail(email, activationCode string) bool {
-	err := godotenv.Load()
+	subject := "Activate Your Account"
+	body := fmt.Sprintf("Click the following link to activate your account: %s/v1/activate/%s", u.cfg.Server.BaseURL, activationCode)
+
+	err := u.mailer.Send([]string{email}, subject, body)
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		log.Printf("send activation email to %s: %v", email, err)
+		return false
 	}
+	return true
+}
 
-	smtpServer := os.Getenv("SMTP_SERVER")
-	smtpPort := os.Getenv("SMTP_PORT")
-	smtpUsername := os.Getenv("SMTP_USERNAME")
-	smtpPassword := os.Getenv("SMTP_PASSWORD")
-
-	to := []string{email}
-	subject := "Activate Your Account"
-	body := fmt.Sprintf("Click the following link to activate your account: http://localhost:8000/v1/activate/%s", activationCode)
-	message := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s", strings.Join(to, ","), subject, body)
+// tokenRateLimit check if user can request new token by email, it return
+// message when the request is limited.
+func (u *UserHandlerV1) tokenRateLimit(userTokenModel *models.UserTokenModel, userID int, purpose models.TokenPurpose) (string, error) {
+	lastToken, err := userTokenModel.LastToken(userID, purpose)
+	if err == nil && time.Since(lastToken.CreatedAt) < u.cfg.Account.ResendCooldown.Duration {
+		return "Please wait before requesting a new email.", nil
+	}
 
-	auth := smtp.PlainAuth("", smtpUsername, smtpPassword, smtpServer)
-	err = smtp.SendMail(smtpServer+":"+smtpPort, auth, smtpUsername, to, []byte(message))
-	if err == nil {
-		return true
-	} else {
-		return false
+	count, err := userTokenModel.CountSince(userID, purpose, time.Now().Add(-time.Hour))
+	if err != nil {
+		return "", err
+	}
+	if count >= int64(u.cfg.Account.ResendLimit) {
+		return "Too many requests, please try again later.", nil
 	}
+	return "", nil
 }
 
 // RegisterHandler is handler to regitration user.
 func (u *UserHandlerV1) RegisterHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
 	payloads := struct {
 		FirstName string `json:"first_name" validate:"required"`
 		LastName  string `json:"last_name" validate:"required"`
@@ -88,7 +90,6 @@ func (u *UserHandlerV1) RegisterHandler(ctx *gin.Context) {
 	}
 
 	// validate field
-	validate = validator.New(validator.WithRequiredStructEnabled())
 	err = validate.Struct(&payloads)
 	if err != nil {
 		if _, ok := err.(*validator.InvalidValidationError); ok {
@@ -118,7 +119,7 @@ func (u *UserHandlerV1) RegisterHandler(ctx *gin.Context) {
 	}
 
 	// save user to db
-	err = models.CreateNewUser(&user)
+	err = models.NewUserModel(db).CreateNewUser(&user)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -128,16 +129,17 @@ func (u *UserHandlerV1) RegisterHandler(ctx *gin.Context) {
 	}
 
 	// generate activation code
-	activationCode := u.generateActivationCode()
-
-	// Save activation code and expiration time in the map
-	activationData[activationCode] = ActivationData{
-		Email:          payloads.Email,
-		ExpirationTime: time.Now().Add(24 * time.Hour),
+	activationCode, err := models.NewUserTokenModel(db).CreateToken(user.ID, models.ACTIVATION, u.cfg.Account.ActivationTTL.Duration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
 	}
 
 	// Send email activation
-	if !u.sendActivationEmail(activationData[activationCode].Email, activationCode) {
+	if !u.sendActivationEmail(user.Email, activationCode) {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": "Failed to send activation code to your email.",
@@ -153,10 +155,11 @@ func (u *UserHandlerV1) RegisterHandler(ctx *gin.Context) {
 
 // ActivationHandler is handler for activation account/user
 func (u *UserHandlerV1) ActivationHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
 	activationCode := ctx.Param("activationCode")
 
-	data, ok := activationData[activationCode]
-	if !ok || time.Now().After(data.ExpirationTime) {
+	userToken, err := models.NewUserTokenModel(db).UseToken(models.ACTIVATION, activationCode)
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": "Invalid or expired activation code.",
@@ -164,25 +167,85 @@ func (u *UserHandlerV1) ActivationHandler(ctx *gin.Context) {
 		return
 	}
 
-	user, err := models.GetUserByEmail(data.Email)
+	err = db.Model(&models.User{}).Where("id = ?", userToken.UserID).Update("is_active", true).Error
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{
+		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
-			"message": fmt.Sprintf("User with email: %s is not found.", data.Email),
+			"message": err.Error(),
 		})
 		return
 	}
 
-	user.IsActive = true
-	models.DB().Save(&user)
-	delete(activationData, activationCode)
-
 	ctx.Writer.Header().Set("Content-Type", "text/html")
 	fmt.Fprintf(ctx.Writer, "<h1>Your account is activated successfully.</h1>")
 }
 
+// ResendActivationHandler is handler to send new activation code. The
+// response is the same for unknown or active email, limited request and
+// failure to send, so it can't be used to check registered email. The
+// failure is only logged.
+func (u *UserHandlerV1) ResendActivationHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
+	payloads := struct {
+		Email string `json:"email" validate:"required,email"`
+	}{}
+	err := ctx.ShouldBindJSON(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Payload error",
+		})
+		return
+	}
+
+	err = validate.Struct(&payloads)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validation error",
+		})
+		return
+	}
+
+	successMessage := gin.H{
+		"status":  "success",
+		"message": "If your account is not yet active, a new activation link is sent to your email.",
+	}
+
+	user, err := models.NewUserModel(db).GetUserByEmail(payloads.Email)
+	if err != nil || user.IsActive {
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+
+	userTokenModel := models.NewUserTokenModel(db)
+	message, err := u.tokenRateLimit(userTokenModel, user.ID, models.ACTIVATION)
+	if err != nil {
+		log.Printf("resend activation to user %d: %v", user.ID, err)
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+	if message != "" {
+		log.Printf("resend activation to user %d is limited: %s", user.ID, message)
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+
+	activationCode, err := userTokenModel.CreateToken(user.ID, models.ACTIVATION, u.cfg.Account.ActivationTTL.Duration)
+	if err != nil {
+		log.Printf("resend activation to user %d: %v", user.ID, err)
+		ctx.JSON(http.StatusOK, successMessage)
+		return
+	}
+
+	// failure to send is logged by sendActivationEmail.
+	u.sendActivationEmail(user.Email, activationCode)
+	ctx.JSON(http.StatusOK, successMessage)
+}
+
 // GetTokenHandler is handler to generate new token.
 func (u *UserHandlerV1) GetTokenHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
 	payloads := struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
@@ -198,7 +261,7 @@ func (u *UserHandlerV1) GetTokenHandler(ctx *gin.Context) {
 
 	var user models.User
 	if strings.Contains(payloads.Username, "@") {
-		user, err = models.GetUserByEmail(payloads.Username)
+		user, err = models.NewUserModel(db).GetUserByEmail(payloads.Username)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
@@ -207,7 +270,7 @@ func (u *UserHandlerV1) GetTokenHandler(ctx *gin.Context) {
 			return
 		}
 	} else {
-		user, err = models.GetUserByUsername(payloads.Username)
+		user, err = models.NewUserModel(db).GetUserByUsername(payloads.Username)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
@@ -235,12 +298,16 @@ func (u *UserHandlerV1) GetTokenHandler(ctx *gin.Context) {
 		return
 	}
 
-	credential := auth.Credential{
-		UserID:   user.ID,
-		Username: user.Username,
+	session, err := models.NewSessionModel(db).CreateSession(user.ID, ctx.Request.UserAgent(), ctx.ClientIP(), u.cfg.JWT.RefreshTTL.Duration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
 	}
 
-	token, err := auth.GetToken(credential)
+	tokens, err := u.issueTokens(db, user, session)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -249,16 +316,17 @@ func (u *UserHandlerV1) GetTokenHandler(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Generate new token is successfully.",
-		"token":   token,
-	})
+	db.Model(&user).Update("is_logined", true)
+
+	tokens["status"] = "success"
+	tokens["message"] = "Generate new token is successfully."
+	ctx.JSON(http.StatusOK, tokens)
 }
 
 // CheckAuthHandler is handler to check authentication user.
 func (u *UserHandlerV1) CheckAuthHandler(ctx *gin.Context) {
-	user, err := getUserFromContext(ctx.Request)
+	db := u.db.WithContext(ctx.Request.Context())
+	user, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"status":  "error",
@@ -272,6 +340,7 @@ func (u *UserHandlerV1) CheckAuthHandler(ctx *gin.Context) {
 
 // ChangePasswordHandler handler to change password user account.
 func (u *UserHandlerV1) ChangePasswordHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
 	payloads := struct {
 		OldPassword        string `json:"old_password"`
 		NewPassword        string `json:"new_password"`
@@ -287,7 +356,7 @@ func (u *UserHandlerV1) ChangePasswordHandler(ctx *gin.Context) {
 		return
 	}
 
-	user, err := getUserFromContext(ctx.Request)
+	user, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"status":  "error",
@@ -315,7 +384,7 @@ func (u *UserHandlerV1) ChangePasswordHandler(ctx *gin.Context) {
 	// update and save password
 	newPassword := auth.EncryptionPassword(payloads.NewPasswordConfirm)
 	user.Password = newPassword
-	models.DB().Save(&user)
+	db.Save(&user)
 	ctx.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Update password is successfully.",
@@ -324,6 +393,7 @@ func (u *UserHandlerV1) ChangePasswordHandler(ctx *gin.Context) {
 
 // UpdateInfoUserHandler handler to update infor user.
 func (u *UserHandlerV1) UpdateInfoUserHandler(ctx *gin.Context) {
+	db := u.db.WithContext(ctx.Request.Context())
 	payloads := struct {
 		FirstName string                `form:"first_name"`
 		LastName  string                `form:"last_name"`
@@ -340,7 +410,7 @@ func (u *UserHandlerV1) UpdateInfoUserHandler(ctx *gin.Context) {
 	}
 
 	// get user context
-	user, err := getUserFromContext(ctx.Request)
+	user, err := getUserFromContext(db, ctx.Request)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
@@ -389,6 +459,6 @@ func (u *UserHandlerV1) UpdateInfoUserHandler(ctx *gin.Context) {
 	}
 
 	// save
-	models.DB().Save(&user)
+	db.Save(&user)
 	ctx.JSON(http.StatusOK, user)
 }
@@ -1,5 +1,14 @@
 package handlers
 
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
 func isAllowedExtension(ext string) bool {
 	allowedExtension := map[string]bool{
 		".jpg":  true,
@@ -10,3 +19,69 @@ func isAllowedExtension(ext string) bool {
 
 	return allowedExtension[ext]
 }
+
+// isAllowedAttachment check extension of file that can be attached to lesson
+// or submitted, e.g. documents and archives.
+func isAllowedAttachment(ext string) bool {
+	allowedExtension := map[string]bool{
+		".pdf":  true,
+		".doc":  true,
+		".docx": true,
+		".ppt":  true,
+		".pptx": true,
+		".xls":  true,
+		".xlsx": true,
+		".txt":  true,
+		".md":   true,
+		".csv":  true,
+		".zip":  true,
+	}
+
+	return allowedExtension[ext] || isAllowedExtension(ext)
+}
+
+// validatePayloads validate payloads and write the errors to response.
+func validatePayloads(ctx *gin.Context, payloads any) bool {
+	err := validate.Struct(payloads)
+	if err != nil {
+		errorMessages := []string{}
+		for _, e := range err.(validator.ValidationErrors) {
+			errorMessage := fmt.Sprintf("Error validation in field %s, condition: %s", e.Field(), e.ActualTag())
+			errorMessages = append(errorMessages, errorMessage)
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":   "error",
+			"message":  "Validation error",
+			"messages": errorMessages,
+		})
+		return false
+	}
+	return true
+}
+
+// reorderIDs check that ids is every id of existing items without
+// duplicate, id can be number or slug.
+func reorderIDs[K comparable](ctx *gin.Context, name string, existing []K, ids []K) bool {
+	remaining := make(map[K]bool, len(existing))
+	for _, id := range existing {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": fmt.Sprintf("%s %v is not found or given twice.", name, id),
+			})
+			return false
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("All %ss must be given.", strings.ToLower(name)),
+		})
+		return false
+	}
+	return true
+}
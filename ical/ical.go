@@ -0,0 +1,92 @@
+// Package ical write iCalendar (RFC 5545) feed of events, it's only support
+// what is needed to subscribe meetings in calendar apps.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// ContentType is media type of iCalendar feed.
+const ContentType = "text/calendar; charset=utf-8"
+
+const timeLayout = "20060102T150405Z"
+
+// Calendar is feed of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event is single event in calendar, UID must be stable so calendar apps
+// update the same event when it's changed.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Modified    time.Time
+}
+
+// line breaks of any kind become \n, a raw CR end the content line.
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape text value of property.
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// writeLine write content line folded at 75 octets without split UTF-8
+// character.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		i := limit
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+		buf.WriteString(line[:i])
+		buf.WriteString("\r\n ")
+		line = line[i:]
+		// the leading space is counted in the next line.
+		limit = 74
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// Bytes return calendar in iCalendar format.
+func (c Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//KafeKoding//KafeKoding API//ID")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escape(event.UID))
+		writeLine(&buf, "DTSTAMP:"+event.Modified.UTC().Format(timeLayout))
+		writeLine(&buf, "LAST-MODIFIED:"+event.Modified.UTC().Format(timeLayout))
+		writeLine(&buf, "DTSTART:"+event.Start.UTC().Format(timeLayout))
+		writeLine(&buf, "DTEND:"+event.End.UTC().Format(timeLayout))
+		writeLine(&buf, "SUMMARY:"+escape(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escape(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(&buf, "URL:"+event.URL)
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
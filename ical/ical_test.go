@@ -0,0 +1,107 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Golang Dasar", "Golang Dasar"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"a\r\nb", `a\nb`},
+		{"a\nb", `a\nb`},
+		{"a\rb", `a\nb`},
+		{"a\r\rb", `a\n\nb`},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.value); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Pertemuan 1"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"76 octets", "SUMMARY:" + strings.Repeat("a", 68)},
+		{"long", "DESCRIPTION:" + strings.Repeat("abcdefghij", 30)},
+		{"multi byte", "SUMMARY:" + strings.Repeat("kelas é 日本 ", 20)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeLine(&buf, tt.line)
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line %q doesn't end with CRLF", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d has %d octets, maximum 75", i, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("folded line %d doesn't start with space", i)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d split a UTF-8 character: %q", i, line)
+				}
+			}
+			if len(tt.line) <= 75 && len(lines) != 1 {
+				t.Errorf("line of %d octets is folded", len(tt.line))
+			}
+
+			// unfolding remove CRLF followed by a space.
+			if got := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); got != tt.line {
+				t.Errorf("unfolded line = %q, want %q", got, tt.line)
+			}
+		})
+	}
+}
+
+func TestCalendarBytes(t *testing.T) {
+	start := time.Date(2026, time.October, 7, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	calendar := Calendar{
+		Name: "Golang Dasar",
+		Events: []Event{{
+			UID:         "meeting-1@kafekoding",
+			Summary:     "Pertemuan 1, perkenalan",
+			Description: "Bawa laptop;\r\ninstall Go",
+			Start:       start,
+			End:         start.Add(2 * time.Hour),
+			Modified:    start,
+		}},
+	}
+
+	out := string(calendar.Bytes())
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Golang Dasar\r\n",
+		"UID:meeting-1@kafekoding\r\n",
+		"DTSTART:20261007T120000Z\r\n",
+		"DTEND:20261007T140000Z\r\n",
+		"SUMMARY:Pertemuan 1\\, perkenalan\r\n",
+		"DESCRIPTION:Bawa laptop\\;\\ninstall Go\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("calendar doesn't contain %q:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "URL:") {
+		t.Error("calendar contains URL of event without URL")
+	}
+}
@@ -0,0 +1,43 @@
+// This package is a package that functions to send email.
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/Aeroxee/kafekoding-api/config"
+)
+
+// Mailer is struct to send email with smtp.
+type Mailer struct {
+	cfg config.SMTP
+}
+
+// New is function to create new mailer.
+func New(cfg config.SMTP) *Mailer {
+	return &Mailer{
+		cfg: cfg,
+	}
+}
+
+// Send is function to send email to target. When smtp server is not
+// configured the message is written to log, it's useful in development.
+func (m *Mailer) Send(to []string, subject, body string) error {
+	from := m.cfg.From
+	if from == "" {
+		from = m.cfg.Username
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", from, strings.Join(to, ","), subject, body)
+	if m.cfg.Server == "" {
+		log.Printf("mailer: smtp server is not configured, email is not sent:\n%s", message)
+		return nil
+	}
+
+	auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Server)
+	addr := m.cfg.Server + ":" + strconv.Itoa(m.cfg.Port)
+	return smtp.SendMail(addr, auth, from, to, []byte(message))
+}
@@ -2,14 +2,60 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/Aeroxee/kafekoding-api/models"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func Authentication() gin.HandlerFunc {
+// VerifyToken is function to verify token and check that the token and its
+// session is not revoked.
+func VerifyToken(db *gorm.DB, token string) (auth.Claims, error) {
+	claims, _, _, err := verifyToken(db, token)
+	return claims, err
+}
+
+func verifyToken(db *gorm.DB, token string) (auth.Claims, models.User, models.Session, error) {
+	var user models.User
+	var session models.Session
+	claims, err := auth.VerifyToken(token)
+	if err != nil {
+		return claims, user, session, err
+	}
+
+	err = db.Where("id = ?", claims.Credential.UserID).First(&user).Error
+	if err != nil {
+		return claims, user, session, errors.New("user of this token is not found")
+	}
+
+	if user.TokenVersion != claims.Credential.TokenVersion {
+		return claims, user, session, errors.New("token is revoked")
+	}
+
+	if claims.ID != "" {
+		revoked, err := models.NewRevokedTokenModel(db).IsRevoked(claims.ID)
+		if err != nil {
+			return claims, user, session, err
+		}
+		if revoked {
+			return claims, user, session, errors.New("token is revoked")
+		}
+	}
+
+	if claims.Credential.SessionID != "" {
+		session, err = models.NewSessionModel(db).GetActiveSession(claims.Credential.SessionID)
+		if err != nil {
+			return claims, user, session, errors.New("session is revoked")
+		}
+	}
+	return claims, user, session, nil
+}
+
+func Authentication(db *gorm.DB) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authenticationHeader := ctx.Request.Header.Get("Authorization")
 		if !strings.Contains(authenticationHeader, "Bearer") {
@@ -20,7 +66,7 @@ func Authentication() gin.HandlerFunc {
 			return
 		}
 		token := strings.Replace(authenticationHeader, "Bearer ", "", -1)
-		claims, err := auth.VerifyToken(token)
+		claims, user, session, err := verifyToken(db.WithContext(ctx.Request.Context()), token)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"status":  "error",
@@ -29,8 +75,14 @@ func Authentication() gin.HandlerFunc {
 			return
 		}
 
-		newContext := context.WithValue(context.Background(), &auth.UserAuth{}, claims)
+		if session.ID != "" {
+			models.NewSessionModel(db.WithContext(ctx.Request.Context())).
+				Touch(session, ctx.Request.UserAgent(), ctx.ClientIP())
+		}
+
+		newContext := context.WithValue(ctx.Request.Context(), &auth.UserAuth{}, claims)
 		ctx.Request = ctx.Request.WithContext(newContext)
+		ctx.Set(userKey, user)
 		ctx.Next()
 	}
 }
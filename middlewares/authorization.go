@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+	"github.com/Aeroxee/kafekoding-api/policy"
+	"github.com/gin-gonic/gin"
+)
+
+const userKey = "user"
+
+// CurrentUser is function to get user that is set by Authentication middleware.
+func CurrentUser(ctx *gin.Context) (models.User, bool) {
+	value, ok := ctx.Get(userKey)
+	if !ok {
+		return models.User{}, false
+	}
+	user, ok := value.(models.User)
+	return user, ok
+}
+
+// Authorize is middleware to allow request only when the user can do action,
+// use it after Authentication. Check of action on a resource is done in the
+// handler with policy.Can because the resource is loaded there.
+func Authorize(action policy.Action) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, ok := CurrentUser(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Authentication is required.",
+			})
+			return
+		}
+
+		if !policy.Can(user, action, nil) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "You don't have permission to do this action.",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout is middleware to set deadline of request context, database query
+// with this context is canceled when the deadline is exceeded.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		newContext, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(newContext)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,160 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Schema of the first version, the models are copied here so change of
+// the models later don't change this migration.
+
+type user struct {
+	ID           int     `gorm:"primaryKey"`
+	FirstName    string  `gorm:"size:50"`
+	LastName     string  `gorm:"size:50"`
+	Username     string  `gorm:"size:50;uniqueIndex"`
+	Email        string  `gorm:"size:50;uniqueIndex"`
+	Avatar       *string `gorm:"size:255"`
+	Password     string  `gorm:"size:128"`
+	IsActive     bool    `gorm:"default:false"`
+	IsLogined    bool    `gorm:"default:false"`
+	Type         int8    `gorm:"default:1"`
+	UpdatedAt    time.Time
+	DateJoined   time.Time `gorm:"autoCreateTime"`
+	Articles     []article `gorm:"foreignKey:UserID"`
+	ClassMentors []*class  `gorm:"many2many:classes_user_mentor"`
+	ClassMembers []*class  `gorm:"many2many:classes_user_member"`
+}
+
+func (user) TableName() string {
+	return "users"
+}
+
+type class struct {
+	ID          int     `gorm:"primaryKey"`
+	Title       string  `gorm:"size:50;unique"`
+	Slug        string  `gorm:"size:60;uniqueIndex"`
+	Description string  `gorm:"type:text"`
+	Logo        *string `gorm:"size:255"`
+	IsActive    bool    `gorm:"default:false"`
+	UpdatedAt   time.Time
+	CreatedAt   time.Time
+	DeletedAt   gorm.DeletedAt  `gorm:"index"`
+	Mentors     []*user         `gorm:"many2many:classes_user_mentor"`
+	Members     []*user         `gorm:"many2many:classes_user_member"`
+	Images      []*classImage   `gorm:"foreignKey:ClassID"`
+	Meetings    []*classMeeting `gorm:"foreignKey:ClassID"`
+}
+
+func (class) TableName() string {
+	return "classes"
+}
+
+type classMeeting struct {
+	ID          int `gorm:"primaryKey"`
+	ClassID     int
+	Title       string `gorm:"size:50"`
+	Slug        string `gorm:"size:60;uniqueIndex"`
+	Content     string `gorm:"type:text"`
+	OpenedAt    time.Time
+	ClosedAt    time.Time
+	UpdatedAt   time.Time
+	CreatedAt   time.Time
+	DeletedAt   gorm.DeletedAt           `gorm:"index"`
+	Attendances []classMeetingAttendance `gorm:"foreignKey:MeetingID"`
+}
+
+func (classMeeting) TableName() string {
+	return "class_meetings"
+}
+
+type classImage struct {
+	ID        int `gorm:"primaryKey"`
+	ClassID   int
+	Image     string  `gorm:"size:255"`
+	Caption   *string `gorm:"size:255"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (classImage) TableName() string {
+	return "class_images"
+}
+
+type classMeetingAttendance struct {
+	ID        int `gorm:"primaryKey"`
+	MeetingID int
+	Users     []*user `gorm:"many2many:classes_meetingattendance_user"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (classMeetingAttendance) TableName() string {
+	return "class_meeting_attendances"
+}
+
+type classPermission struct {
+	ID        int `gorm:"primaryKey"`
+	UserID    int
+	ClassID   int
+	Type      string `gorm:"size:4"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+func (classPermission) TableName() string {
+	return "class_permissions"
+}
+
+type article struct {
+	ID        int `gorm:"primaryKey"`
+	UserID    int
+	Title     string `gorm:"size:50"`
+	Slug      string `gorm:"size:60;uniqueIndex"`
+	Content   string `gorm:"type:text"`
+	Views     int    `gorm:"default:0"`
+	Status    string `gorm:"default:DRAFTED"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt   `gorm:"index"`
+	Comments  []articleComment `gorm:"foreignKey:ArticleID"`
+}
+
+func (article) TableName() string {
+	return "articles"
+}
+
+type articleComment struct {
+	ID        int `gorm:"primaryKey"`
+	ArticleID int
+	UserID    int
+	Text      string `gorm:"type:text"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (articleComment) TableName() string {
+	return "article_comments"
+}
+
+// Initial schema, it's the same as the schema created by the old
+// AutoMigrate at startup, so existing database can apply it safely.
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "initial",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&user{}, &class{}, &classMeeting{}, &classImage{},
+				&classMeetingAttendance{}, &classPermission{}, &article{}, &articleComment{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("classes_meetingattendance_user", "classes_user_member",
+				"classes_user_mentor", &articleComment{}, &article{}, &classPermission{},
+				&classMeetingAttendance{}, &classImage{}, &classMeeting{}, &class{}, &user{})
+		},
+	})
+}
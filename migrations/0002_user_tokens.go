@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userToken struct {
+	ID        int       `gorm:"primaryKey"`
+	UserID    int       `gorm:"index"`
+	Purpose   string    `gorm:"size:20;index"`
+	TokenHash string    `gorm:"size:64;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (userToken) TableName() string {
+	return "user_tokens"
+}
+
+// Persistent activation token, it replace activation code in memory.
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "user_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&userToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&userToken{})
+		},
+	})
+}
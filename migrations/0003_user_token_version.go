@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type userTokenVersion struct {
+	TokenVersion int `gorm:"default:0"`
+}
+
+func (userTokenVersion) TableName() string {
+	return "users"
+}
+
+// Token version of user, it's increased to revoke all token of the user.
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "user_token_version",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&userTokenVersion{}, "TokenVersion")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &user{}, "token_version")
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type refreshToken struct {
+	ID           int    `gorm:"primaryKey"`
+	FamilyID     string `gorm:"size:36;index"`
+	UserID       int    `gorm:"index"`
+	TokenHash    string `gorm:"size:64;uniqueIndex"`
+	TokenVersion int
+	ExpiresAt    time.Time `gorm:"index"`
+	UsedAt       *time.Time
+	RevokedAt    *time.Time
+	CreatedAt    time.Time
+}
+
+func (refreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// Refresh token families issued at login.
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&refreshToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&refreshToken{})
+		},
+	})
+}
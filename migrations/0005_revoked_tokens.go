@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type revokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:36"`
+	UserID    int       `gorm:"index"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (revokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// Revocation list of access token for logout.
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "revoked_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&revokedToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&revokedToken{})
+		},
+	})
+}
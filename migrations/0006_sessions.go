@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type session struct {
+	ID         string `gorm:"primaryKey;size:36"`
+	UserID     int    `gorm:"index"`
+	UserAgent  string `gorm:"size:255"`
+	IP         string `gorm:"size:45"`
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time `gorm:"index"`
+	RevokedAt  *time.Time
+}
+
+func (session) TableName() string {
+	return "sessions"
+}
+
+// Login sessions of user.
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&session{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&session{})
+		},
+	})
+}
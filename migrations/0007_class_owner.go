@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type classOwner struct {
+	OwnerID *int
+}
+
+func (classOwner) TableName() string {
+	return "classes"
+}
+
+// Owner mentor of class, existing class is owned by its first mentor.
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "class_owner",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&classOwner{}, "OwnerID"); err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE classes SET owner_id = " +
+				"(SELECT MIN(user_id) FROM classes_user_mentor WHERE classes_user_mentor.class_id = classes.id) " +
+				"WHERE owner_id IS NULL").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &class{}, "owner_id")
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type classPermissionRequest struct {
+	ID         int    `gorm:"primaryKey"`
+	UserID     int    `gorm:"index"`
+	User       *user  `gorm:"foreignKey:UserID"`
+	ClassID    int    `gorm:"index"`
+	Type       string `gorm:"size:4"`
+	Status     string `gorm:"size:10;index"`
+	Message    string `gorm:"size:255"`
+	Note       string `gorm:"size:255"`
+	ReviewedBy *int
+	ReviewedAt *time.Time
+	UpdatedAt  time.Time
+	CreatedAt  time.Time
+}
+
+func (classPermissionRequest) TableName() string {
+	return "class_permissions"
+}
+
+type notification struct {
+	ID        int    `gorm:"primaryKey"`
+	UserID    int    `gorm:"index"`
+	Title     string `gorm:"size:100"`
+	Message   string `gorm:"type:text"`
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (notification) TableName() string {
+	return "notifications"
+}
+
+// Review status of class join and leave request, and notification of user.
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "class_requests",
+		Up: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			for _, column := range []string{"Status", "Message", "Note", "ReviewedBy", "ReviewedAt"} {
+				if err := migrator.AddColumn(&classPermissionRequest{}, column); err != nil {
+					return err
+				}
+			}
+			// sqlite add constraint by rebuilding the table, so the indexes
+			// are created after it.
+			if err := migrator.CreateConstraint(&classPermissionRequest{}, "User"); err != nil {
+				return err
+			}
+			if err := createIndexes(tx, &classPermissionRequest{}); err != nil {
+				return err
+			}
+			return migrator.CreateTable(&notification{})
+		},
+		Down: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			if err := migrator.DropTable(&notification{}); err != nil {
+				return err
+			}
+			for _, index := range []string{"UserID", "ClassID", "Status"} {
+				if err := migrator.DropIndex(&classPermissionRequest{}, index); err != nil {
+					return err
+				}
+			}
+			if err := migrator.DropConstraint(&classPermissionRequest{}, "User"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &classPermission{}, "status", "message", "note", "reviewed_by", "reviewed_at")
+		},
+	})
+}
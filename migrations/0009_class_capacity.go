@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type classCapacity struct {
+	Capacity           int `gorm:"default:0"`
+	EnrollmentOpensAt  *time.Time
+	EnrollmentClosesAt *time.Time
+}
+
+func (classCapacity) TableName() string {
+	return "classes"
+}
+
+type classWaitlist struct {
+	ID        int   `gorm:"primaryKey"`
+	ClassID   int   `gorm:"uniqueIndex:idx_class_waitlist_user"`
+	UserID    int   `gorm:"uniqueIndex:idx_class_waitlist_user"`
+	User      *user `gorm:"foreignKey:UserID"`
+	CreatedAt time.Time
+}
+
+func (classWaitlist) TableName() string {
+	return "class_waitlists"
+}
+
+// Capacity and enrollment window of class, and waitlist of full class.
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "class_capacity",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range []string{"Capacity", "EnrollmentOpensAt", "EnrollmentClosesAt"} {
+				if err := tx.Migrator().AddColumn(&classCapacity{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateTable(&classWaitlist{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&classWaitlist{}); err != nil {
+				return err
+			}
+			return dropColumns(tx, &class{}, "capacity", "enrollment_opens_at", "enrollment_closes_at")
+		},
+	})
+}
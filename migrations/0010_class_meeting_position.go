@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type classMeetingPosition struct {
+	ID        int    `gorm:"primaryKey"`
+	ClassID   int    `gorm:"uniqueIndex:idx_class_meeting_slug"`
+	Slug      string `gorm:"size:60;uniqueIndex:idx_class_meeting_slug"`
+	Position  int    `gorm:"default:0"`
+	OpenedAt  time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (classMeetingPosition) TableName() string {
+	return "class_meetings"
+}
+
+// Position of meeting in class, and slug of meeting is unique in its class
+// instead of in all classes.
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "class_meeting_position",
+		Up: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			if err := migrator.AddColumn(&classMeetingPosition{}, "Position"); err != nil {
+				return err
+			}
+
+			// existing meetings are ordered by their open time.
+			var meetings []classMeetingPosition
+			err := tx.Unscoped().Order("class_id").Order("opened_at").Order("id").Find(&meetings).Error
+			if err != nil {
+				return err
+			}
+			position, classID := 0, 0
+			for _, meeting := range meetings {
+				if meeting.ClassID != classID {
+					position, classID = 0, meeting.ClassID
+				}
+				position++
+				err := tx.Model(&classMeetingPosition{}).Where("id = ?", meeting.ID).
+					Update("position", position).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := migrator.DropIndex(&classMeeting{}, "Slug"); err != nil {
+				return err
+			}
+			return migrator.CreateIndex(&classMeetingPosition{}, "idx_class_meeting_slug")
+		},
+		Down: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			if err := migrator.DropIndex(&classMeetingPosition{}, "idx_class_meeting_slug"); err != nil {
+				return err
+			}
+			if err := migrator.CreateIndex(&classMeeting{}, "Slug"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &classMeeting{}, "position")
+		},
+	})
+}
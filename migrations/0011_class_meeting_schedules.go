@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type classMeetingSchedule struct {
+	ID              int    `gorm:"primaryKey"`
+	ClassID         int    `gorm:"index"`
+	Title           string `gorm:"size:40"`
+	Content         string `gorm:"type:text"`
+	TimeZone        string `gorm:"size:64"`
+	StartsAt        time.Time
+	DurationMinutes int
+	Rule            string `gorm:"size:255"`
+	Exceptions      string `gorm:"type:text"`
+	UpdatedAt       time.Time
+	CreatedAt       time.Time
+}
+
+func (classMeetingSchedule) TableName() string {
+	return "class_meeting_schedules"
+}
+
+type classMeetingOccurrence struct {
+	ScheduleID   *int `gorm:"index"`
+	OccurrenceAt *time.Time
+}
+
+func (classMeetingOccurrence) TableName() string {
+	return "class_meetings"
+}
+
+// Recurring schedule of class meetings.
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "class_meeting_schedules",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&classMeetingSchedule{}); err != nil {
+				return err
+			}
+			migrator := tx.Migrator()
+			for _, column := range []string{"ScheduleID", "OccurrenceAt"} {
+				if err := migrator.AddColumn(&classMeetingOccurrence{}, column); err != nil {
+					return err
+				}
+			}
+			return migrator.CreateIndex(&classMeetingOccurrence{}, "ScheduleID")
+		},
+		Down: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			if err := migrator.DropIndex(&classMeetingOccurrence{}, "ScheduleID"); err != nil {
+				return err
+			}
+			if err := dropColumns(tx, &classMeetingPosition{}, "schedule_id", "occurrence_at"); err != nil {
+				return err
+			}
+			return migrator.DropTable(&classMeetingSchedule{})
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type meetingAttendanceSecret struct {
+	MeetingID int    `gorm:"uniqueIndex"`
+	Secret    string `gorm:"size:64"`
+}
+
+func (meetingAttendanceSecret) TableName() string {
+	return "class_meeting_attendances"
+}
+
+// Secret of rotating check-in code, and one attendance for a meeting.
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "meeting_attendance_secret",
+		Up: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			if err := migrator.AddColumn(&meetingAttendanceSecret{}, "Secret"); err != nil {
+				return err
+			}
+			return migrator.CreateIndex(&meetingAttendanceSecret{}, "MeetingID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&meetingAttendanceSecret{}, "MeetingID"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &classMeetingAttendance{}, "secret")
+		},
+	})
+}
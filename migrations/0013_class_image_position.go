@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type classImagePosition struct {
+	ID       int `gorm:"primaryKey"`
+	ClassID  int `gorm:"index"`
+	Position int `gorm:"default:0"`
+}
+
+func (classImagePosition) TableName() string {
+	return "class_images"
+}
+
+// Position of image in class gallery.
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "class_image_position",
+		Up: func(tx *gorm.DB) error {
+			migrator := tx.Migrator()
+			if err := migrator.AddColumn(&classImagePosition{}, "Position"); err != nil {
+				return err
+			}
+
+			// existing images are ordered by their upload.
+			var images []classImagePosition
+			err := tx.Order("class_id").Order("id").Find(&images).Error
+			if err != nil {
+				return err
+			}
+			position, classID := 0, 0
+			for _, image := range images {
+				if image.ClassID != classID {
+					position, classID = 0, image.ClassID
+				}
+				position++
+				err := tx.Model(&classImagePosition{}).Where("id = ?", image.ID).
+					Update("position", position).Error
+				if err != nil {
+					return err
+				}
+			}
+			return migrator.CreateIndex(&classImagePosition{}, "ClassID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&classImagePosition{}, "ClassID"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &classImage{}, "position")
+		},
+	})
+}
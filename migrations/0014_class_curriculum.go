@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type classModule struct {
+	ID          int    `gorm:"primaryKey"`
+	ClassID     int    `gorm:"index"`
+	Title       string `gorm:"size:100"`
+	Description string `gorm:"type:text"`
+	Position    int    `gorm:"default:0"`
+	UpdatedAt   time.Time
+	CreatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	Lessons     []classLesson  `gorm:"foreignKey:ModuleID"`
+}
+
+func (classModule) TableName() string {
+	return "class_modules"
+}
+
+type classLesson struct {
+	ID          int    `gorm:"primaryKey"`
+	ModuleID    int    `gorm:"index"`
+	MeetingID   *int   `gorm:"index"`
+	Title       string `gorm:"size:100"`
+	Content     string `gorm:"type:text"`
+	Position    int    `gorm:"default:0"`
+	UpdatedAt   time.Time
+	CreatedAt   time.Time
+	DeletedAt   gorm.DeletedAt          `gorm:"index"`
+	Meeting     *classMeeting           `gorm:"foreignKey:MeetingID"`
+	Attachments []classLessonAttachment `gorm:"foreignKey:LessonID"`
+}
+
+func (classLesson) TableName() string {
+	return "class_lessons"
+}
+
+type classLessonAttachment struct {
+	ID        int    `gorm:"primaryKey"`
+	LessonID  int    `gorm:"index"`
+	File      string `gorm:"size:255"`
+	Name      string `gorm:"size:255"`
+	Size      int64
+	CreatedAt time.Time
+}
+
+func (classLessonAttachment) TableName() string {
+	return "class_lesson_attachments"
+}
+
+// Curriculum of class, modules with lessons and their files.
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "class_curriculum",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&classModule{}, &classLesson{}, &classLessonAttachment{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&classLessonAttachment{}, &classLesson{}, &classModule{})
+		},
+	})
+}
@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type classAssignment struct {
+	ID           int    `gorm:"primaryKey"`
+	ClassID      int    `gorm:"index"`
+	MeetingID    *int   `gorm:"index"`
+	Title        string `gorm:"size:100"`
+	Instructions string `gorm:"type:text"`
+	DueAt        time.Time
+	MaxScore     int
+	AllowLate    bool `gorm:"default:false"`
+	LateUntil    *time.Time
+	LatePenalty  int `gorm:"default:0"`
+	UpdatedAt    time.Time
+	CreatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	Meeting      *classMeeting  `gorm:"foreignKey:MeetingID"`
+}
+
+func (classAssignment) TableName() string {
+	return "class_assignments"
+}
+
+type classSubmission struct {
+	ID           int     `gorm:"primaryKey"`
+	AssignmentID int     `gorm:"uniqueIndex:idx_class_submission_user"`
+	UserID       int     `gorm:"uniqueIndex:idx_class_submission_user"`
+	Link         *string `gorm:"size:255"`
+	Note         string  `gorm:"type:text"`
+	SubmittedAt  time.Time
+	IsLate       bool `gorm:"default:false"`
+	Score        *int
+	FinalScore   *int
+	GradedBy     *int
+	GradedAt     *time.Time
+	UpdatedAt    time.Time
+	CreatedAt    time.Time
+	User         *user                    `gorm:"foreignKey:UserID"`
+	Files        []classSubmissionFile    `gorm:"foreignKey:SubmissionID"`
+	Comments     []classSubmissionComment `gorm:"foreignKey:SubmissionID"`
+}
+
+func (classSubmission) TableName() string {
+	return "class_submissions"
+}
+
+type classSubmissionFile struct {
+	ID           int    `gorm:"primaryKey"`
+	SubmissionID int    `gorm:"index"`
+	File         string `gorm:"size:255"`
+	Name         string `gorm:"size:255"`
+	Size         int64
+	CreatedAt    time.Time
+}
+
+func (classSubmissionFile) TableName() string {
+	return "class_submission_files"
+}
+
+type classSubmissionComment struct {
+	ID           int `gorm:"primaryKey"`
+	SubmissionID int `gorm:"index"`
+	UserID       int
+	Message      string `gorm:"type:text"`
+	CreatedAt    time.Time
+	User         *user `gorm:"foreignKey:UserID"`
+}
+
+func (classSubmissionComment) TableName() string {
+	return "class_submission_comments"
+}
+
+// Assignments of class with submissions of members, their files and
+// feedback comments.
+func init() {
+	register(Migration{
+		Version: 15,
+		Name:    "class_assignments",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&classAssignment{}, &classSubmission{},
+				&classSubmissionFile{}, &classSubmissionComment{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&classSubmissionComment{}, &classSubmissionFile{},
+				&classSubmission{}, &classAssignment{})
+		},
+	})
+}
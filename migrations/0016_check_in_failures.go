@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type checkInFailure struct {
+	ID        int       `gorm:"primaryKey"`
+	MeetingID int       `gorm:"index:idx_check_in_failure_user"`
+	UserID    int       `gorm:"index:idx_check_in_failure_user"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (checkInFailure) TableName() string {
+	return "check_in_failures"
+}
+
+// Wrong check-in code of user, it's counted to limit guessing of the code.
+func init() {
+	register(Migration{
+		Version: 16,
+		Name:    "check_in_failures",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&checkInFailure{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&checkInFailure{})
+		},
+	})
+}
@@ -0,0 +1,217 @@
+// This package is a package that functions to manage versioned database schema.
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one versioned change of database schema.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration is model to record applied migration.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255"`
+	AppliedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName return table name of schema migration.
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status is status of one migration.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+var registered []Migration
+
+// register is function to add migration, it's called from init of migration file.
+func register(migration Migration) {
+	for _, m := range registered {
+		if m.Version == migration.Version {
+			panic(fmt.Sprintf("migrations: duplicate version %d", migration.Version))
+		}
+	}
+
+	registered = append(registered, migration)
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Version < registered[j].Version
+	})
+}
+
+// dropColumns is function to drop columns of table, model is the table
+// after the drop. sqlite drop column by rebuilding the table, it lose the
+// indexes of the table, so the indexes of model are created again.
+func dropColumns(tx *gorm.DB, model interface{}, columns ...string) error {
+	migrator := tx.Migrator()
+	for _, column := range columns {
+		if err := migrator.DropColumn(model, column); err != nil {
+			return err
+		}
+	}
+	if tx.Dialector.Name() != "sqlite" {
+		return nil
+	}
+	return createIndexes(tx, model)
+}
+
+// createIndexes is function to create all indexes of model.
+func createIndexes(tx *gorm.DB, model interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range stmt.Schema.ParseIndexes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := tx.Migrator().CreateIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Migrator is struct to apply and revert migration.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New is function to create migrator with all registered migration.
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: registered,
+	}
+}
+
+func (m *Migrator) applied() (map[int]SchemaMigration, error) {
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// Up is function to apply pending migration until target version,
+// target 0 mean latest version.
+func (m *Migrator) Up(target int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name}).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("migrations: up %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// Down is function to revert the last applied migration by given steps.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(result) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("migrations: down %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// Status is function to get status of all migration.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, migration := range m.migrations {
+		status := Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// Pending is function to count migration that is not applied yet.
+func (m *Migrator) Pending() (int, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
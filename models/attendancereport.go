@@ -0,0 +1,154 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// AttendanceReport is attendance matrix of class, each row is a member and
+// each column is a meeting.
+type AttendanceReport struct {
+	ClassID    int                       `json:"class_id"`
+	From       *time.Time                `json:"from"`
+	To         *time.Time                `json:"to"`
+	Meetings   []AttendanceReportMeeting `json:"meetings"`
+	Members    []AttendanceReportMember  `json:"members"`
+	Percentage float64                   `json:"percentage"`
+}
+
+// AttendanceReportMeeting is column of attendance report.
+type AttendanceReportMeeting struct {
+	ID         int       `json:"id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	OpenedAt   time.Time `json:"opened_at"`
+	Attended   int       `json:"attended"`
+	Percentage float64   `json:"percentage"`
+}
+
+// AttendanceReportMember is row of attendance report, Present has the same
+// order as meetings of the report.
+type AttendanceReportMember struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	// IsMember is false for user that attend but already leave the class.
+	IsMember   bool    `json:"is_member"`
+	Present    []bool  `json:"present"`
+	Attended   int     `json:"attended"`
+	Percentage float64 `json:"percentage"`
+}
+
+// percentage return n of total in percent with 2 decimals.
+func percentage(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)*10000/float64(total)) / 100
+}
+
+// GetClassReport is function to get attendance report of class members for
+// meetings that already opened at the given time, from and to limit opened
+// time of the meetings when not nil.
+func (c *ClassMeetingAttendanceModel) GetClassReport(class Class, from, to *time.Time, now time.Time) (AttendanceReport, error) {
+	report := AttendanceReport{
+		ClassID:  class.ID,
+		From:     from,
+		To:       to,
+		Meetings: []AttendanceReportMeeting{},
+		Members:  []AttendanceReportMember{},
+	}
+
+	var meetings []ClassMeeting
+	query := c.db.Where("class_id = ? AND opened_at <= ?", class.ID, now.UTC())
+	if from != nil {
+		query = query.Where("opened_at >= ?", from.UTC())
+	}
+	if to != nil {
+		query = query.Where("opened_at < ?", to.UTC())
+	}
+	err := query.Order("opened_at ASC, position ASC, id ASC").Find(&meetings).Error
+	if err != nil {
+		return report, err
+	}
+
+	ids := make([]int, len(meetings))
+	for i, meeting := range meetings {
+		ids[i] = meeting.ID
+	}
+	var attendances []ClassMeetingAttendance
+	if len(ids) > 0 {
+		err = c.db.Where("meeting_id IN ?", ids).Preload("Users").Find(&attendances).Error
+		if err != nil {
+			return report, err
+		}
+	}
+
+	// present[meetingID][userID]
+	present := map[int]map[int]bool{}
+	for _, attendance := range attendances {
+		present[attendance.MeetingID] = map[int]bool{}
+		for _, user := range attendance.Users {
+			present[attendance.MeetingID][user.ID] = true
+		}
+	}
+
+	// current members first, then user that attend but leave the class.
+	users := []*User{}
+	seen := map[int]bool{}
+	for _, member := range class.Members {
+		users = append(users, member)
+		seen[member.ID] = true
+	}
+	for _, attendance := range attendances {
+		for _, user := range attendance.Users {
+			if !seen[user.ID] && !class.IsMentor(user.ID) {
+				users = append(users, user)
+				seen[user.ID] = true
+			}
+		}
+	}
+
+	for _, meeting := range meetings {
+		attended := 0
+		for _, user := range users {
+			if present[meeting.ID][user.ID] {
+				attended++
+			}
+		}
+		report.Meetings = append(report.Meetings, AttendanceReportMeeting{
+			ID:         meeting.ID,
+			Title:      meeting.Title,
+			Slug:       meeting.Slug,
+			OpenedAt:   meeting.OpenedAt,
+			Attended:   attended,
+			Percentage: percentage(attended, len(users)),
+		})
+	}
+
+	total := 0
+	for _, user := range users {
+		row := AttendanceReportMember{
+			ID:        user.ID,
+			Username:  user.Username,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+			IsMember:  class.IsMember(user.ID),
+			Present:   make([]bool, len(meetings)),
+		}
+		for i, meeting := range meetings {
+			if present[meeting.ID][user.ID] {
+				row.Present[i] = true
+				row.Attended++
+			}
+		}
+		row.Percentage = percentage(row.Attended, len(meetings))
+		total += row.Attended
+		report.Members = append(report.Members, row)
+	}
+	report.Percentage = percentage(total, len(meetings)*len(users))
+	return report, nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,39 +10,264 @@ import (
 
 // Class is struct for implement class in kafekoding.
 type Class struct {
-	ID          int             `gorm:"primaryKey" json:"id"`
-	Title       string          `gorm:"size:50;unique" json:"title"`
-	Slug        string          `gorm:"size:60;uniqueIndex" json:"slug"`
-	Description string          `gorm:"type:text" json:"description"`
-	Logo        *string         `gorm:"size:255" json:"logo"`
-	IsActive    bool            `gorm:"default:false" json:"is_active"`
-	UpdatedAt   time.Time       `json:"updated_at"`
-	CreatedAt   time.Time       `json:"created_at"`
-	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"deleted_at"`
-	Mentors     []*User         `gorm:"many2many:classes_user_mentor" json:"mentors"`
-	Members     []*User         `gorm:"many2many:classes_user_member" json:"members"`
-	Images      []*ClassImage   `gorm:"foreignKey:ClassID" json:"images"`
-	Meetings    []*ClassMeeting `gorm:"foreignKey:ClassID" json:"meetings"`
-}
-
-func CreateNewClass(class *Class) error {
-	return DB().Create(class).Error
-}
-
-func GetAllClass(is_active bool) []Class {
+	ID                 int             `gorm:"primaryKey" json:"id"`
+	Title              string          `gorm:"size:50;unique" json:"title"`
+	Slug               string          `gorm:"size:60;uniqueIndex" json:"slug"`
+	Description        string          `gorm:"type:text" json:"description"`
+	Logo               *string         `gorm:"size:255" json:"logo"`
+	IsActive           bool            `gorm:"default:false" json:"is_active"`
+	OwnerID            *int            `json:"owner_id"`
+	Capacity           int             `gorm:"default:0" json:"capacity"`
+	EnrollmentOpensAt  *time.Time      `json:"enrollment_opens_at"`
+	EnrollmentClosesAt *time.Time      `json:"enrollment_closes_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	CreatedAt          time.Time       `json:"created_at"`
+	DeletedAt          gorm.DeletedAt  `gorm:"index" json:"deleted_at"`
+	Mentors            []*User         `gorm:"many2many:classes_user_mentor" json:"mentors"`
+	Members            []*User         `gorm:"many2many:classes_user_member" json:"members"`
+	Images             []*ClassImage   `gorm:"foreignKey:ClassID" json:"images"`
+	Meetings           []*ClassMeeting `gorm:"foreignKey:ClassID" json:"meetings"`
+}
+
+// ClassModel struct to class model.
+type ClassModel struct {
+	db *gorm.DB
+}
+
+// NewClassModel is function to run class model.
+func NewClassModel(db *gorm.DB) *ClassModel {
+	return &ClassModel{
+		db: db,
+	}
+}
+
+// CreateNewClass is function to create new class.
+func (c *ClassModel) CreateNewClass(class *Class) error {
+	return c.db.Create(class).Error
+}
+
+// GetAllClass is function to get all class by active status.
+func (c *ClassModel) GetAllClass(is_active bool) []Class {
 	var classes []Class
-	DB().Model(&Class{}).Where("is_active = ?", is_active).Order(clause.OrderByColumn{
+	c.db.Model(&Class{}).Where("is_active = ?", is_active).Order(clause.OrderByColumn{
 		Column: clause.Column{Name: "title"},
 		Desc:   false,
-	}).Preload("Mentors").Preload("Members").Preload("Images").Preload("Meetings").
+	}).Preload("Mentors").Preload("Members").Preload("Images", orderImages).Preload("Meetings", orderMeetings).
 		Find(&classes)
 
 	return classes
 }
 
-func GetClassBySlug(slug string) (Class, error) {
+// GetClassBySlug is function to get class by given slug.
+func (c *ClassModel) GetClassBySlug(slug string) (Class, error) {
 	var class Class
-	err := DB().Model(&Class{}).Where("slug = ?", slug).Preload("Mentors").Preload("Members").
-		Preload("Images").Preload("Meetings").First(&class).Error
+	err := c.db.Model(&Class{}).Where("slug = ?", slug).Preload("Mentors").Preload("Members").
+		Preload("Images", orderImages).Preload("Meetings", orderMeetings).First(&class).Error
 	return class, err
 }
+
+// GetUserClasses is function to get classes that user mentor or is member
+// of, with their meetings.
+func (c *ClassModel) GetUserClasses(userID int) ([]Class, error) {
+	var classes []Class
+	err := c.db.Model(&Class{}).
+		Where("id IN (?) OR id IN (?)",
+			c.db.Table("classes_user_mentor").Select("class_id").Where("user_id = ?", userID),
+			c.db.Table("classes_user_member").Select("class_id").Where("user_id = ?", userID)).
+		Order("title ASC").Preload("Meetings", orderMeetings).Find(&classes).Error
+	return classes, err
+}
+
+// IsOwner is function to check if user is the owner mentor of class.
+func (c Class) IsOwner(userID int) bool {
+	return c.OwnerID != nil && *c.OwnerID == userID
+}
+
+// IsMentor is function to check if user is owner or co-mentor of class.
+func (c Class) IsMentor(userID int) bool {
+	if c.IsOwner(userID) {
+		return true
+	}
+	for _, mentor := range c.Mentors {
+		if mentor.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMember is function to check if user is member of class.
+func (c Class) IsMember(userID int) bool {
+	for _, member := range c.Members {
+		if member.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddMember is function to add user as member of class.
+func (c *ClassModel) AddMember(class *Class, user *User) error {
+	return c.db.Model(class).Association("Members").Append(user)
+}
+
+// RemoveMember is function to remove user from member of class.
+func (c *ClassModel) RemoveMember(class *Class, user *User) error {
+	return c.db.Model(class).Association("Members").Delete(user)
+}
+
+// IsEnrollmentOpen is function to check if user can request to join class
+// at the given time.
+func (c Class) IsEnrollmentOpen(now time.Time) bool {
+	if c.EnrollmentOpensAt != nil && now.Before(*c.EnrollmentOpensAt) {
+		return false
+	}
+	if c.EnrollmentClosesAt != nil && !now.Before(*c.EnrollmentClosesAt) {
+		return false
+	}
+	return true
+}
+
+// IsFull is function to check if all seats of class are taken, class without
+// capacity is never full.
+func (c *ClassModel) IsFull(class *Class) (bool, error) {
+	if class.Capacity <= 0 {
+		return false, nil
+	}
+
+	var count int64
+	err := c.db.Table("classes_user_member").Where("class_id = ?", class.ID).Count(&count).Error
+	return count >= int64(class.Capacity), err
+}
+
+// lockClass lock row of class until the end of transaction, so the seats
+// are counted by one transaction at a time. The capacity is reloaded from
+// the locked row.
+func lockClass(tx *gorm.DB, class *Class) error {
+	var locked Class
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "capacity").
+		Where("id = ?", class.ID).First(&locked).Error
+	if err != nil {
+		return err
+	}
+	class.Capacity = locked.Capacity
+	return nil
+}
+
+// Enroll is function to add user as member of class, the user is added to
+// waitlist when the class is full. It return true when user is waitlisted.
+func (c *ClassModel) Enroll(class *Class, user *User) (bool, error) {
+	waitlisted := false
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockClass(tx, class); err != nil {
+			return err
+		}
+
+		full, err := NewClassModel(tx).IsFull(class)
+		if err != nil {
+			return err
+		}
+		if full {
+			waitlisted = true
+			return NewClassWaitlistModel(tx).Add(class.ID, user.ID)
+		}
+
+		if err := NewClassModel(tx).AddMember(class, user); err != nil {
+			return err
+		}
+		_, err = NewClassWaitlistModel(tx).Remove(class.ID, user.ID)
+		return err
+	})
+	return waitlisted, err
+}
+
+// Leave is function to remove user from member of class and move waiting
+// users to the free seat. It return the users that become member.
+func (c *ClassModel) Leave(class *Class, user *User) ([]User, error) {
+	var promoted []User
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockClass(tx, class); err != nil {
+			return err
+		}
+		if err := NewClassModel(tx).RemoveMember(class, user); err != nil {
+			return err
+		}
+
+		var err error
+		promoted, err = NewClassModel(tx).FillFromWaitlist(class)
+		return err
+	})
+	return promoted, err
+}
+
+// FillSeats is function to move waiting users to the free seats after the
+// capacity is changed, the class is locked like in Enroll and Leave. It
+// return the users that become member.
+func (c *ClassModel) FillSeats(class *Class) ([]User, error) {
+	var promoted []User
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockClass(tx, class); err != nil {
+			return err
+		}
+
+		var err error
+		promoted, err = NewClassModel(tx).FillFromWaitlist(class)
+		return err
+	})
+	return promoted, err
+}
+
+// FillFromWaitlist is function to move waiting users to member until the
+// class is full, it must be called in transaction after lockClass.
+func (c *ClassModel) FillFromWaitlist(class *Class) ([]User, error) {
+	var promoted []User
+	waitlistModel := NewClassWaitlistModel(c.db)
+	for {
+		full, err := c.IsFull(class)
+		if err != nil || full {
+			return promoted, err
+		}
+
+		entry, err := waitlistModel.Next(class.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return promoted, nil
+		}
+		if err != nil {
+			return promoted, err
+		}
+		if entry.User == nil {
+			if _, err := waitlistModel.Remove(class.ID, entry.UserID); err != nil {
+				return promoted, err
+			}
+			continue
+		}
+
+		if err := c.AddMember(class, entry.User); err != nil {
+			return promoted, err
+		}
+		if _, err := waitlistModel.Remove(class.ID, entry.UserID); err != nil {
+			return promoted, err
+		}
+		promoted = append(promoted, *entry.User)
+	}
+}
+
+// AddMentor is function to add user as mentor of class, the first mentor of
+// class without owner become the owner.
+func (c *ClassModel) AddMentor(class *Class, user *User) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(class).Association("Mentors").Append(user); err != nil {
+			return err
+		}
+		if class.OwnerID != nil {
+			return nil
+		}
+		class.OwnerID = &user.ID
+		return tx.Model(class).Update("owner_id", user.ID).Error
+	})
+}
+
+// RemoveMentor is function to remove user from mentor of class.
+func (c *ClassModel) RemoveMentor(class *Class, user *User) error {
+	return c.db.Model(class).Association("Mentors").Delete(user)
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/config"
+	"github.com/Aeroxee/kafekoding-api/migrations"
+	"gorm.io/gorm"
+)
+
+// openTestDB open in-memory sqlite database with the full schema.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := config.Default().Database
+	cfg.Driver = config.SQLITE
+	cfg.DSN = ":memory:"
+
+	db, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Close(db) })
+	if _, err := migrations.New(db).Up(0); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createTestUsers(t *testing.T, db *gorm.DB, usernames ...string) []User {
+	t.Helper()
+	users := make([]User, len(usernames))
+	for i, username := range usernames {
+		users[i] = User{
+			FirstName: username,
+			Username:  username,
+			Email:     username + "@kafekoding.local",
+			Password:  "password",
+			IsActive:  true,
+			Type:      MEMBER,
+		}
+		if err := NewUserModel(db).CreateNewUser(&users[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return users
+}
+
+func TestClassStaff(t *testing.T) {
+	owner := 1
+	class := Class{
+		OwnerID: &owner,
+		Mentors: []*User{{ID: 1}, {ID: 2}},
+		Members: []*User{{ID: 3}},
+	}
+
+	tests := []struct {
+		name     string
+		class    Class
+		userID   int
+		isOwner  bool
+		isMentor bool
+		isMember bool
+	}{
+		{"owner", class, 1, true, true, false},
+		{"co-mentor", class, 2, false, true, false},
+		{"member", class, 3, false, false, true},
+		{"outsider", class, 4, false, false, false},
+		{"owner not in mentors", Class{OwnerID: &owner}, 1, true, true, false},
+		{"class without mentor", Class{}, 1, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.class.IsOwner(tt.userID); got != tt.isOwner {
+				t.Errorf("IsOwner() = %v, want %v", got, tt.isOwner)
+			}
+			if got := tt.class.IsMentor(tt.userID); got != tt.isMentor {
+				t.Errorf("IsMentor() = %v, want %v", got, tt.isMentor)
+			}
+			if got := tt.class.IsMember(tt.userID); got != tt.isMember {
+				t.Errorf("IsMember() = %v, want %v", got, tt.isMember)
+			}
+		})
+	}
+}
+
+func TestEnrollCapacity(t *testing.T) {
+	db := openTestDB(t)
+	users := createTestUsers(t, db, "budi", "sari", "andi")
+	classModel := NewClassModel(db)
+	class := Class{Title: "Golang Dasar", Slug: "golang-dasar", Capacity: 2}
+	if err := classModel.CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []bool{false, false, true} {
+		waitlisted, err := classModel.Enroll(&class, &users[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if waitlisted != want {
+			t.Errorf("%s: waitlisted = %v, want %v", users[i].Username, waitlisted, want)
+		}
+	}
+
+	full, err := classModel.IsFull(&class)
+	if err != nil || !full {
+		t.Fatalf("IsFull() = %v, %v, want true", full, err)
+	}
+	position, err := NewClassWaitlistModel(db).Position(class.ID, users[2].ID)
+	if err != nil || position != 1 {
+		t.Fatalf("Position() = %d, %v, want 1", position, err)
+	}
+
+	// enroll again keep the user in the same place of waitlist.
+	waitlisted, err := classModel.Enroll(&class, &users[2])
+	if err != nil || !waitlisted {
+		t.Fatalf("Enroll() again = %v, %v, want waitlisted", waitlisted, err)
+	}
+	entries, err := NewClassWaitlistModel(db).GetClassWaitlist(class.ID)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("GetClassWaitlist() = %d entries, %v, want 1", len(entries), err)
+	}
+}
+
+func TestEnrollWithoutCapacity(t *testing.T) {
+	db := openTestDB(t)
+	users := createTestUsers(t, db, "budi", "sari", "andi")
+	classModel := NewClassModel(db)
+	class := Class{Title: "Golang Dasar", Slug: "golang-dasar"}
+	if err := classModel.CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range users {
+		waitlisted, err := classModel.Enroll(&class, &users[i])
+		if err != nil || waitlisted {
+			t.Fatalf("%s: Enroll() = %v, %v, want member", users[i].Username, waitlisted, err)
+		}
+	}
+}
+
+func TestLeavePromoteWaitlist(t *testing.T) {
+	db := openTestDB(t)
+	users := createTestUsers(t, db, "budi", "sari", "andi")
+	classModel := NewClassModel(db)
+	class := Class{Title: "Golang Dasar", Slug: "golang-dasar", Capacity: 1}
+	if err := classModel.CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+	for i := range users {
+		if _, err := classModel.Enroll(&class, &users[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	promoted, err := classModel.Leave(&class, &users[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(promoted) != 1 || promoted[0].ID != users[1].ID {
+		t.Fatalf("promoted = %v, want %s", promoted, users[1].Username)
+	}
+
+	class, err = classModel.GetClassBySlug("golang-dasar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if class.IsMember(users[0].ID) || !class.IsMember(users[1].ID) || class.IsMember(users[2].ID) {
+		t.Errorf("members = %v, want only %s", class.Members, users[1].Username)
+	}
+	position, err := NewClassWaitlistModel(db).Position(class.ID, users[2].ID)
+	if err != nil || position != 1 {
+		t.Fatalf("Position() = %d, %v, want 1", position, err)
+	}
+
+	// capacity is increased, the next waiting user become member.
+	if err := db.Model(&class).Update("capacity", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	promoted, err = classModel.FillSeats(&class)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(promoted) != 1 || promoted[0].ID != users[2].ID {
+		t.Fatalf("promoted = %v, want %s", promoted, users[2].Username)
+	}
+}
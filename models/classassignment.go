@@ -0,0 +1,240 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClassAssignment is homework of class, it can be given in a meeting.
+type ClassAssignment struct {
+	ID           int       `gorm:"primaryKey" json:"id"`
+	ClassID      int       `gorm:"index" json:"class_id"`
+	MeetingID    *int      `gorm:"index" json:"meeting_id"`
+	Title        string    `gorm:"size:100" json:"title"`
+	Instructions string    `gorm:"type:text" json:"instructions"`
+	DueAt        time.Time `json:"due_at"`
+	MaxScore     int       `json:"max_score"`
+	// AllowLate accept submission after DueAt until LateUntil, or without
+	// limit when LateUntil is nil. Score of late submission is reduced by
+	// LatePenalty percent.
+	AllowLate   bool           `gorm:"default:false" json:"allow_late"`
+	LateUntil   *time.Time     `json:"late_until"`
+	LatePenalty int            `gorm:"default:0" json:"late_penalty"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	Meeting     *ClassMeeting  `gorm:"foreignKey:MeetingID" json:"meeting,omitempty"`
+}
+
+// ClassSubmission is answer of member to assignment, a member has one
+// submission for each assignment.
+type ClassSubmission struct {
+	ID           int       `gorm:"primaryKey" json:"id"`
+	AssignmentID int       `gorm:"uniqueIndex:idx_class_submission_user" json:"assignment_id"`
+	UserID       int       `gorm:"uniqueIndex:idx_class_submission_user" json:"user_id"`
+	Link         *string   `gorm:"size:255" json:"link"`
+	Note         string    `gorm:"type:text" json:"note"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	IsLate       bool      `gorm:"default:false" json:"is_late"`
+	// Score is given by mentor, FinalScore is the score after late penalty.
+	Score      *int                     `json:"score"`
+	FinalScore *int                     `json:"final_score"`
+	GradedBy   *int                     `json:"graded_by"`
+	GradedAt   *time.Time               `json:"graded_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+	CreatedAt  time.Time                `json:"created_at"`
+	User       *User                    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Files      []ClassSubmissionFile    `gorm:"foreignKey:SubmissionID" json:"files"`
+	Comments   []ClassSubmissionComment `gorm:"foreignKey:SubmissionID" json:"comments"`
+}
+
+// ClassSubmissionFile is file of submission, Name is the uploaded file name.
+type ClassSubmissionFile struct {
+	ID           int       `gorm:"primaryKey" json:"id"`
+	SubmissionID int       `gorm:"index" json:"submission_id"`
+	File         string    `gorm:"size:255" json:"file"`
+	Name         string    `gorm:"size:255" json:"name"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ClassSubmissionComment is feedback of mentor or reply of member on
+// submission.
+type ClassSubmissionComment struct {
+	ID           int       `gorm:"primaryKey" json:"id"`
+	SubmissionID int       `gorm:"index" json:"submission_id"`
+	UserID       int       `json:"user_id"`
+	Message      string    `gorm:"type:text" json:"message"`
+	CreatedAt    time.Time `json:"created_at"`
+	User         *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// IsLate is function to check if submission at the given time is late.
+func (a ClassAssignment) IsLate(at time.Time) bool {
+	return at.After(a.DueAt)
+}
+
+// AcceptsAt is function to check if submission is accepted at the given time.
+func (a ClassAssignment) AcceptsAt(at time.Time) bool {
+	if !a.IsLate(at) {
+		return true
+	}
+	return a.AllowLate && (a.LateUntil == nil || !at.After(*a.LateUntil))
+}
+
+// FinalScore is function to get score after penalty of late submission.
+func (a ClassAssignment) FinalScore(score int, late bool) int {
+	if !late || a.LatePenalty <= 0 {
+		return score
+	}
+	return int(math.Round(float64(score) * float64(100-a.LatePenalty) / 100))
+}
+
+// ClassAssignmentModel struct to class assignment model.
+type ClassAssignmentModel struct {
+	db *gorm.DB
+}
+
+// NewClassAssignmentModel is function to run class assignment model.
+func NewClassAssignmentModel(db *gorm.DB) *ClassAssignmentModel {
+	return &ClassAssignmentModel{
+		db: db,
+	}
+}
+
+// preloadSubmission load user, files and comments of submission.
+func preloadSubmission(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").
+		Preload("Files", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at").Order("id")
+		}).
+		Preload("Comments.User")
+}
+
+// GetClassAssignments is function to get assignments of class by due date.
+func (c *ClassAssignmentModel) GetClassAssignments(classID int) ([]ClassAssignment, error) {
+	var assignments []ClassAssignment
+	err := c.db.Where("class_id = ?", classID).Preload("Meeting").
+		Order("due_at").Order("id").Find(&assignments).Error
+	return assignments, err
+}
+
+// GetAssignment is function to get assignment of class by given id.
+func (c *ClassAssignmentModel) GetAssignment(classID, id int) (ClassAssignment, error) {
+	var assignment ClassAssignment
+	err := c.db.Where("class_id = ? AND id = ?", classID, id).Preload("Meeting").
+		First(&assignment).Error
+	return assignment, err
+}
+
+// CreateAssignment is function to create assignment.
+func (c *ClassAssignmentModel) CreateAssignment(assignment *ClassAssignment) error {
+	return c.db.Omit("Meeting").Create(assignment).Error
+}
+
+// UpdateAssignment is function to save changes of assignment.
+func (c *ClassAssignmentModel) UpdateAssignment(assignment *ClassAssignment) error {
+	return c.db.Model(assignment).
+		Select("MeetingID", "Title", "Instructions", "DueAt", "MaxScore", "AllowLate", "LateUntil", "LatePenalty").
+		Updates(assignment).Error
+}
+
+// DeleteAssignment is function to delete assignment.
+func (c *ClassAssignmentModel) DeleteAssignment(assignment ClassAssignment) error {
+	return c.db.Delete(&assignment).Error
+}
+
+// GetUserSubmission is function to get submission of user to assignment.
+func (c *ClassAssignmentModel) GetUserSubmission(assignmentID, userID int) (ClassSubmission, error) {
+	var submission ClassSubmission
+	err := preloadSubmission(c.db).Where("assignment_id = ? AND user_id = ?", assignmentID, userID).
+		First(&submission).Error
+	return submission, err
+}
+
+// GetSubmission is function to get submission by given id.
+func (c *ClassAssignmentModel) GetSubmission(assignmentID, id int) (ClassSubmission, error) {
+	var submission ClassSubmission
+	err := preloadSubmission(c.db).Where("assignment_id = ? AND id = ?", assignmentID, id).
+		First(&submission).Error
+	return submission, err
+}
+
+// GetSubmissions is function to get submissions of assignment by submit
+// time.
+func (c *ClassAssignmentModel) GetSubmissions(assignmentID int) ([]ClassSubmission, error) {
+	var submissions []ClassSubmission
+	err := c.db.Where("assignment_id = ?", assignmentID).Preload("User").
+		Preload("Files", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).Order("submitted_at").Order("id").Find(&submissions).Error
+	return submissions, err
+}
+
+// Submit is function to save submission of user with its files, it replace
+// the previous submission and its files. It return files of the previous
+// submission to be removed by the caller.
+func (c *ClassAssignmentModel) Submit(submission *ClassSubmission, files []ClassSubmissionFile) ([]ClassSubmissionFile, error) {
+	var old []ClassSubmissionFile
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var existing ClassSubmission
+		err := tx.Where("assignment_id = ? AND user_id = ?", submission.AssignmentID, submission.UserID).
+			Limit(1).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		if existing.ID == 0 {
+			if err := tx.Omit("User", "Files", "Comments").Create(submission).Error; err != nil {
+				return err
+			}
+		} else {
+			submission.ID = existing.ID
+			submission.CreatedAt = existing.CreatedAt
+			err := tx.Model(submission).Select("Link", "Note", "SubmittedAt", "IsLate").Updates(submission).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Where("submission_id = ?", existing.ID).Find(&old).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("submission_id = ?", existing.ID).Delete(&ClassSubmissionFile{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(files) == 0 {
+			return nil
+		}
+		for i := range files {
+			files[i].SubmissionID = submission.ID
+		}
+		return tx.Create(&files).Error
+	})
+	submission.Files = files
+	return old, err
+}
+
+// Grade is function to save score of submission given by mentor. Lateness
+// is checked again with the current due date, it may be changed after the
+// submission.
+func (c *ClassAssignmentModel) Grade(assignment ClassAssignment, submission *ClassSubmission, score, graderID int) error {
+	now := time.Now()
+	submission.IsLate = assignment.IsLate(submission.SubmittedAt)
+	finalScore := assignment.FinalScore(score, submission.IsLate)
+	submission.Score = &score
+	submission.FinalScore = &finalScore
+	submission.GradedBy = &graderID
+	submission.GradedAt = &now
+	return c.db.Model(submission).Select("IsLate", "Score", "FinalScore", "GradedBy", "GradedAt").Updates(submission).Error
+}
+
+// AddComment is function to add comment to submission.
+func (c *ClassAssignmentModel) AddComment(comment *ClassSubmissionComment) error {
+	return c.db.Omit("User").Create(comment).Error
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssignmentLateRules(t *testing.T) {
+	due := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	lateUntil := due.Add(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		assignment ClassAssignment
+		at         time.Time
+		late       bool
+		accepted   bool
+		finalScore int
+	}{
+		{"before due", ClassAssignment{DueAt: due, LatePenalty: 20}, due.Add(-time.Minute), false, true, 90},
+		{"at due", ClassAssignment{DueAt: due, LatePenalty: 20}, due, false, true, 90},
+		{"late not allowed", ClassAssignment{DueAt: due}, due.Add(time.Minute), true, false, 90},
+		{"late without limit", ClassAssignment{DueAt: due, AllowLate: true, LatePenalty: 20}, due.Add(48 * time.Hour), true, true, 72},
+		{"late before limit", ClassAssignment{DueAt: due, AllowLate: true, LateUntil: &lateUntil, LatePenalty: 25}, lateUntil, true, true, 68},
+		{"late after limit", ClassAssignment{DueAt: due, AllowLate: true, LateUntil: &lateUntil}, lateUntil.Add(time.Second), true, false, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.assignment.IsLate(tt.at); got != tt.late {
+				t.Errorf("IsLate() = %v, want %v", got, tt.late)
+			}
+			if got := tt.assignment.AcceptsAt(tt.at); got != tt.accepted {
+				t.Errorf("AcceptsAt() = %v, want %v", got, tt.accepted)
+			}
+			if got := tt.assignment.FinalScore(90, tt.late); got != tt.finalScore {
+				t.Errorf("FinalScore() = %d, want %d", got, tt.finalScore)
+			}
+		})
+	}
+}
+
+func TestGradeAfterDueIsExtended(t *testing.T) {
+	db := openTestDB(t)
+	users := createTestUsers(t, db, "budi")
+	class := Class{Title: "Golang Dasar", Slug: "golang-dasar"}
+	if err := NewClassModel(db).CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+
+	due := time.Now().Add(-time.Hour).UTC()
+	assignmentModel := NewClassAssignmentModel(db)
+	assignment := ClassAssignment{ClassID: class.ID, Title: "Tugas 1", DueAt: due, MaxScore: 100, AllowLate: true, LatePenalty: 50}
+	if err := assignmentModel.CreateAssignment(&assignment); err != nil {
+		t.Fatal(err)
+	}
+	submission := ClassSubmission{AssignmentID: assignment.ID, UserID: users[0].ID, SubmittedAt: due.Add(time.Minute), IsLate: true}
+	if _, err := assignmentModel.Submit(&submission, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// mentor extend the due date, the submission is not late anymore.
+	assignment.DueAt = due.Add(time.Hour)
+	if err := assignmentModel.UpdateAssignment(&assignment); err != nil {
+		t.Fatal(err)
+	}
+	if err := assignmentModel.Grade(assignment, &submission, 80, users[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := assignmentModel.GetSubmission(assignment.ID, submission.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.IsLate || saved.FinalScore == nil || *saved.FinalScore != 80 {
+		t.Fatalf("got late %v and final score %v, want not late and 80", saved.IsLate, saved.FinalScore)
+	}
+}
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClassModule is part of class curriculum, it contain ordered lessons.
+type ClassModule struct {
+	ID          int            `gorm:"primaryKey" json:"id"`
+	ClassID     int            `gorm:"index" json:"class_id"`
+	Title       string         `gorm:"size:100" json:"title"`
+	Description string         `gorm:"type:text" json:"description"`
+	Position    int            `gorm:"default:0" json:"position"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	Lessons     []ClassLesson  `gorm:"foreignKey:ModuleID" json:"lessons"`
+}
+
+// ClassLesson is lesson of module with markdown content, it can be linked
+// to the meeting where it's taught.
+type ClassLesson struct {
+	ID          int                     `gorm:"primaryKey" json:"id"`
+	ModuleID    int                     `gorm:"index" json:"module_id"`
+	MeetingID   *int                    `gorm:"index" json:"meeting_id"`
+	Title       string                  `gorm:"size:100" json:"title"`
+	Content     string                  `gorm:"type:text" json:"content"`
+	Position    int                     `gorm:"default:0" json:"position"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	CreatedAt   time.Time               `json:"created_at"`
+	DeletedAt   gorm.DeletedAt          `gorm:"index" json:"deleted_at"`
+	Meeting     *ClassMeeting           `gorm:"foreignKey:MeetingID" json:"meeting,omitempty"`
+	Attachments []ClassLessonAttachment `gorm:"foreignKey:LessonID" json:"attachments"`
+}
+
+// ClassLessonAttachment is file of lesson, Name is the uploaded file name.
+type ClassLessonAttachment struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	LessonID  int       `gorm:"index" json:"lesson_id"`
+	File      string    `gorm:"size:255" json:"file"`
+	Name      string    `gorm:"size:255" json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClassCurriculumModel struct to class curriculum model.
+type ClassCurriculumModel struct {
+	db *gorm.DB
+}
+
+// NewClassCurriculumModel is function to run class curriculum model.
+func NewClassCurriculumModel(db *gorm.DB) *ClassCurriculumModel {
+	return &ClassCurriculumModel{
+		db: db,
+	}
+}
+
+// orderCurriculum is order of modules in class and lessons in module.
+func orderCurriculum(db *gorm.DB) *gorm.DB {
+	return db.Order("position").Order("id")
+}
+
+// preloadLesson load meeting and attachments of lesson.
+func preloadLesson(db *gorm.DB) *gorm.DB {
+	return db.Preload("Meeting").Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+}
+
+// GetCurriculum is function to get modules of class with their lessons in
+// order.
+func (c *ClassCurriculumModel) GetCurriculum(classID int) ([]ClassModule, error) {
+	var modules []ClassModule
+	err := orderCurriculum(c.db.Where("class_id = ?", classID)).
+		Preload("Lessons", func(db *gorm.DB) *gorm.DB {
+			return preloadLesson(orderCurriculum(db))
+		}).Find(&modules).Error
+	return modules, err
+}
+
+// GetModule is function to get module of class by given id.
+func (c *ClassCurriculumModel) GetModule(classID, id int) (ClassModule, error) {
+	var module ClassModule
+	err := c.db.Where("class_id = ? AND id = ?", classID, id).
+		Preload("Lessons", func(db *gorm.DB) *gorm.DB {
+			return preloadLesson(orderCurriculum(db))
+		}).First(&module).Error
+	return module, err
+}
+
+// CreateModule is function to create module at the end of class curriculum.
+func (c *ClassCurriculumModel) CreateModule(module *ClassModule) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		position, err := nextPosition(tx, &ClassModule{}, "class_id", module.ClassID)
+		if err != nil {
+			return err
+		}
+
+		module.Position = position
+		return tx.Create(module).Error
+	})
+}
+
+// UpdateModule is function to save title and description of module.
+func (c *ClassCurriculumModel) UpdateModule(module *ClassModule) error {
+	return c.db.Model(module).Select("Title", "Description").Updates(module).Error
+}
+
+// ReorderModules is function to set position of modules by the order of
+// given ids, every module of the class must be given.
+func (c *ClassCurriculumModel) ReorderModules(classID int, ids []int) error {
+	return reorder(c.db, &ClassModule{}, "class_id", classID, ids)
+}
+
+// deleteAttachments delete files of lessons, it return the deleted files.
+func deleteAttachments(tx *gorm.DB, lessonIDs []int) ([]ClassLessonAttachment, error) {
+	var attachments []ClassLessonAttachment
+	if len(lessonIDs) == 0 {
+		return attachments, nil
+	}
+	if err := tx.Where("lesson_id IN ?", lessonIDs).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("lesson_id IN ?", lessonIDs).Delete(&ClassLessonAttachment{}).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DeleteModule is function to delete module with its lessons and their
+// files. It return the files to be removed by the caller.
+func (c *ClassCurriculumModel) DeleteModule(module ClassModule) ([]ClassLessonAttachment, error) {
+	var attachments []ClassLessonAttachment
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var lessonIDs []int
+		if err := tx.Model(&ClassLesson{}).Where("module_id = ?", module.ID).Pluck("id", &lessonIDs).Error; err != nil {
+			return err
+		}
+
+		var err error
+		attachments, err = deleteAttachments(tx, lessonIDs)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("module_id = ?", module.ID).Delete(&ClassLesson{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&module).Error
+	})
+	return attachments, err
+}
+
+// GetLesson is function to get lesson in module of class by given id.
+func (c *ClassCurriculumModel) GetLesson(classID, id int) (ClassLesson, error) {
+	var lesson ClassLesson
+	err := preloadLesson(c.db).
+		Joins("JOIN class_modules ON class_modules.id = class_lessons.module_id AND class_modules.deleted_at IS NULL").
+		Where("class_modules.class_id = ? AND class_lessons.id = ?", classID, id).
+		First(&lesson).Error
+	return lesson, err
+}
+
+// CreateLesson is function to create lesson at the end of module.
+func (c *ClassCurriculumModel) CreateLesson(lesson *ClassLesson) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		position, err := nextPosition(tx, &ClassLesson{}, "module_id", lesson.ModuleID)
+		if err != nil {
+			return err
+		}
+
+		lesson.Position = position
+		return tx.Omit("Meeting").Create(lesson).Error
+	})
+}
+
+// UpdateLesson is function to save title, content and meeting of lesson.
+func (c *ClassCurriculumModel) UpdateLesson(lesson *ClassLesson) error {
+	return c.db.Model(lesson).Select("Title", "Content", "MeetingID").Updates(lesson).Error
+}
+
+// ReorderLessons is function to set position of lessons by the order of
+// given ids, every lesson of the module must be given.
+func (c *ClassCurriculumModel) ReorderLessons(moduleID int, ids []int) error {
+	return reorder(c.db, &ClassLesson{}, "module_id", moduleID, ids)
+}
+
+// DeleteLesson is function to delete lesson with its files. It return the
+// files to be removed by the caller.
+func (c *ClassCurriculumModel) DeleteLesson(lesson ClassLesson) ([]ClassLessonAttachment, error) {
+	var attachments []ClassLessonAttachment
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		attachments, err = deleteAttachments(tx, []int{lesson.ID})
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&lesson).Error
+	})
+	return attachments, err
+}
+
+// AddAttachments is function to add files to lesson.
+func (c *ClassCurriculumModel) AddAttachments(lesson ClassLesson, attachments []ClassLessonAttachment) error {
+	for i := range attachments {
+		attachments[i].LessonID = lesson.ID
+	}
+	return c.db.Create(&attachments).Error
+}
+
+// GetAttachment is function to get file of lesson by given id.
+func (c *ClassCurriculumModel) GetAttachment(lessonID, id int) (ClassLessonAttachment, error) {
+	var attachment ClassLessonAttachment
+	err := c.db.Where("lesson_id = ? AND id = ?", lessonID, id).First(&attachment).Error
+	return attachment, err
+}
+
+// DeleteAttachment is function to delete file of lesson, the file is removed
+// by the caller.
+func (c *ClassCurriculumModel) DeleteAttachment(attachment ClassLessonAttachment) error {
+	return c.db.Delete(&attachment).Error
+}
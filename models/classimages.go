@@ -8,10 +8,76 @@ import (
 
 type ClassImage struct {
 	ID        int            `gorm:"primaryKey" json:"id"`
-	ClassID   int            `json:"class_id"`
+	ClassID   int            `gorm:"index" json:"class_id"`
 	Image     string         `gorm:"size:255" json:"image"`
 	Caption   *string        `gorm:"size:255" json:"caption"`
+	Position  int            `gorm:"default:0" json:"position"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
+
+// ClassImageModel struct to class image model.
+type ClassImageModel struct {
+	db *gorm.DB
+}
+
+// NewClassImageModel is function to run class image model.
+func NewClassImageModel(db *gorm.DB) *ClassImageModel {
+	return &ClassImageModel{
+		db: db,
+	}
+}
+
+// orderImages is order of images in class gallery.
+func orderImages(db *gorm.DB) *gorm.DB {
+	return db.Order("position").Order("id")
+}
+
+// GetClassImages is function to get images of class gallery in order.
+func (c *ClassImageModel) GetClassImages(classID int) ([]ClassImage, error) {
+	var images []ClassImage
+	err := orderImages(c.db.Where("class_id = ?", classID)).Find(&images).Error
+	return images, err
+}
+
+// GetImage is function to get image of class by given id.
+func (c *ClassImageModel) GetImage(classID, id int) (ClassImage, error) {
+	var image ClassImage
+	err := c.db.Where("class_id = ? AND id = ?", classID, id).First(&image).Error
+	return image, err
+}
+
+// CreateImages is function to add images at the end of class gallery.
+func (c *ClassImageModel) CreateImages(classID int, images []ClassImage) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		position, err := nextPosition(tx, &ClassImage{}, "class_id", classID)
+		if err != nil {
+			return err
+		}
+
+		for i := range images {
+			images[i].ClassID = classID
+			images[i].Position = position + i
+		}
+		return tx.Create(&images).Error
+	})
+}
+
+// UpdateCaption is function to change caption of image, nil remove it.
+func (c *ClassImageModel) UpdateCaption(image *ClassImage, caption *string) error {
+	image.Caption = caption
+	return c.db.Model(image).Select("Caption").Updates(image).Error
+}
+
+// Reorder is function to set position of images by the order of given ids,
+// every image of the class must be given.
+func (c *ClassImageModel) Reorder(classID int, ids []int) error {
+	return reorder(c.db, &ClassImage{}, "class_id", classID, ids)
+}
+
+// DeleteImage is function to delete image permanently, its file is removed
+// by the caller.
+func (c *ClassImageModel) DeleteImage(image ClassImage) error {
+	return c.db.Unscoped().Delete(&image).Error
+}
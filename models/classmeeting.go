@@ -1,21 +1,92 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type ClassMeeting struct {
-	ID          int                      `gorm:"primaryKey" json:"id"`
-	ClassID     int                      `json:"class_id"`
-	Title       string                   `gorm:"size:50" json:"title"`
-	Slug        string                   `gorm:"size:60;uniqueIndex" json:"slug"`
-	Content     string                   `gorm:"type:text" json:"content"`
-	OpenedAt    time.Time                `json:"opened_at"`
-	ClosedAt    time.Time                `json:"closed_at"`
-	UpdatedAt   time.Time                `json:"updated_at"`
-	CreatedAt   time.Time                `json:"created_at"`
-	DeletedAt   gorm.DeletedAt           `gorm:"index" json:"deleted_at"`
-	Attendances []ClassMeetingAttendance `gorm:"foreignKey:MeetingID" json:"attendances"`
+	ID         int    `gorm:"primaryKey" json:"id"`
+	ClassID    int    `gorm:"uniqueIndex:idx_class_meeting_slug" json:"class_id"`
+	Title      string `gorm:"size:50" json:"title"`
+	Slug       string `gorm:"size:60;uniqueIndex:idx_class_meeting_slug" json:"slug"`
+	Content    string `gorm:"type:text" json:"content"`
+	Position   int    `gorm:"default:0" json:"position"`
+	ScheduleID *int   `gorm:"index" json:"schedule_id"`
+	// OccurrenceAt is start of the occurrence of schedule that create
+	// this meeting, it's used to edit this and the following meetings.
+	OccurrenceAt *time.Time               `json:"occurrence_at"`
+	OpenedAt     time.Time                `json:"opened_at"`
+	ClosedAt     time.Time                `json:"closed_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+	CreatedAt    time.Time                `json:"created_at"`
+	DeletedAt    gorm.DeletedAt           `gorm:"index" json:"deleted_at"`
+	Attendances  []ClassMeetingAttendance `gorm:"foreignKey:MeetingID" json:"attendances"`
+}
+
+// ClassMeetingModel struct to class meeting model.
+type ClassMeetingModel struct {
+	db *gorm.DB
+}
+
+// NewClassMeetingModel is function to run class meeting model.
+func NewClassMeetingModel(db *gorm.DB) *ClassMeetingModel {
+	return &ClassMeetingModel{
+		db: db,
+	}
+}
+
+// orderMeetings is order of meetings in class.
+func orderMeetings(db *gorm.DB) *gorm.DB {
+	return db.Order("position").Order("opened_at").Order("id")
+}
+
+// GetClassMeetings is function to get meetings of class in order.
+func (c *ClassMeetingModel) GetClassMeetings(classID int) ([]ClassMeeting, error) {
+	var meetings []ClassMeeting
+	err := orderMeetings(c.db.Where("class_id = ?", classID)).Find(&meetings).Error
+	return meetings, err
+}
+
+// GetMeetingBySlug is function to get meeting of class by given slug.
+func (c *ClassMeetingModel) GetMeetingBySlug(classID int, slug string) (ClassMeeting, error) {
+	var meeting ClassMeeting
+	err := c.db.Where("class_id = ? AND slug = ?", classID, slug).First(&meeting).Error
+	return meeting, err
+}
+
+// UniqueSlug is function to make slug that is not used by other meeting of
+// the class, e.g. "pertemuan-1-2" when "pertemuan-1" is used.
+func (c *ClassMeetingModel) UniqueSlug(classID int, slug string, exceptID int) (string, error) {
+	candidate := slug
+	for i := 2; ; i++ {
+		var count int64
+		err := c.db.Unscoped().Model(&ClassMeeting{}).
+			Where("class_id = ? AND slug = ? AND id <> ?", classID, candidate, exceptID).Count(&count).Error
+		if err != nil || count == 0 {
+			return candidate, err
+		}
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+}
+
+// CreateMeeting is function to create meeting at the end of class meetings.
+func (c *ClassMeetingModel) CreateMeeting(meeting *ClassMeeting) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		position, err := nextPosition(tx, &ClassMeeting{}, "class_id", meeting.ClassID)
+		if err != nil {
+			return err
+		}
+
+		meeting.Position = position
+		return tx.Create(meeting).Error
+	})
+}
+
+// Reorder is function to set position of meetings by the order of given ids,
+// every meeting of the class must be given.
+func (c *ClassMeetingModel) Reorder(classID int, ids []int) error {
+	return reorder(c.db, &ClassMeeting{}, "class_id", classID, ids)
 }
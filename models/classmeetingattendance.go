@@ -3,14 +3,111 @@ package models
 import (
 	"time"
 
+	"github.com/Aeroxee/kafekoding-api/auth"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ClassMeetingAttendance struct {
-	ID        int            `gorm:"primaryKey" json:"id"`
-	MeetingID int            `json:"meeting_id"`
+	ID        int `gorm:"primaryKey" json:"id"`
+	MeetingID int `gorm:"uniqueIndex" json:"meeting_id"`
+	// Secret is secret of rotating check-in code of the meeting.
+	Secret    string         `gorm:"size:64" json:"-"`
 	Users     []*User        `gorm:"many2many:classes_meetingattendance_user" json:"users"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
+
+// CheckInFailure is wrong check-in code sent by user, it's counted to limit
+// guessing of the code.
+type CheckInFailure struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	MeetingID int       `gorm:"index:idx_check_in_failure_user" json:"meeting_id"`
+	UserID    int       `gorm:"index:idx_check_in_failure_user" json:"user_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// ClassMeetingAttendanceModel struct to class meeting attendance model.
+type ClassMeetingAttendanceModel struct {
+	db *gorm.DB
+}
+
+// NewClassMeetingAttendanceModel is function to run class meeting attendance model.
+func NewClassMeetingAttendanceModel(db *gorm.DB) *ClassMeetingAttendanceModel {
+	return &ClassMeetingAttendanceModel{
+		db: db,
+	}
+}
+
+// GetAttendance is function to get attendance of meeting with its users, it's
+// created with new secret when the meeting doesn't have attendance.
+func (c *ClassMeetingAttendanceModel) GetAttendance(meetingID int) (ClassMeetingAttendance, error) {
+	var attendance ClassMeetingAttendance
+	err := c.db.Where("meeting_id = ?", meetingID).Preload("Users").Limit(1).Find(&attendance).Error
+	if err != nil || attendance.Secret != "" {
+		return attendance, err
+	}
+
+	secret, err := auth.RandomToken()
+	if err != nil {
+		return attendance, err
+	}
+	if attendance.ID == 0 {
+		// attendance created by concurrent request is kept with its secret.
+		err = c.db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&ClassMeetingAttendance{MeetingID: meetingID, Secret: secret}).Error
+	} else {
+		// attendance created before check-in code doesn't have secret.
+		err = c.db.Model(&ClassMeetingAttendance{}).
+			Where("id = ? AND (secret IS NULL OR secret = '')", attendance.ID).
+			Update("secret", secret).Error
+	}
+	if err != nil {
+		return attendance, err
+	}
+
+	err = c.db.Where("meeting_id = ?", meetingID).Preload("Users").First(&attendance).Error
+	return attendance, err
+}
+
+// HasUser is function to check if user is already checked in.
+func (a ClassMeetingAttendance) HasUser(userID int) bool {
+	for _, user := range a.Users {
+		if user.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddUser is function to record that user attend the meeting.
+func (c *ClassMeetingAttendanceModel) AddUser(attendance *ClassMeetingAttendance, user *User) error {
+	return c.db.Model(attendance).Association("Users").Append(user)
+}
+
+// RemoveUser is function to remove user from attendance of the meeting.
+func (c *ClassMeetingAttendanceModel) RemoveUser(attendance *ClassMeetingAttendance, user *User) error {
+	return c.db.Model(attendance).Association("Users").Delete(user)
+}
+
+// AddCheckInFailure is function to record wrong check-in code of user.
+func (c *ClassMeetingAttendanceModel) AddCheckInFailure(meetingID, userID int) error {
+	return c.db.Create(&CheckInFailure{MeetingID: meetingID, UserID: userID}).Error
+}
+
+// CountCheckInFailures is function to count wrong check-in code of user to
+// meeting since given time.
+func (c *ClassMeetingAttendanceModel) CountCheckInFailures(meetingID, userID int, since time.Time) (int64, error) {
+	var count int64
+	err := c.db.Model(&CheckInFailure{}).Where("meeting_id = ? AND user_id = ? AND created_at > ?", meetingID, userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteCheckInFailures is function to delete wrong check-in code recorded
+// before given time.
+func (c *ClassMeetingAttendanceModel) DeleteCheckInFailures(before time.Time) (int64, error) {
+	result := c.db.Where("created_at < ?", before).Delete(&CheckInFailure{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,264 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/recurrence"
+	"github.com/gosimple/slug"
+	"gorm.io/gorm"
+)
+
+// ClassMeetingSchedule is recurring meeting of class, its meetings are
+// created when the schedule is created.
+type ClassMeetingSchedule struct {
+	ID              int       `gorm:"primaryKey" json:"id"`
+	ClassID         int       `gorm:"index" json:"class_id"`
+	Title           string    `gorm:"size:40" json:"title"`
+	Content         string    `gorm:"type:text" json:"content"`
+	TimeZone        string    `gorm:"size:64" json:"time_zone"`
+	StartsAt        time.Time `json:"starts_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Rule            string    `gorm:"size:255" json:"rule"`
+	// Exceptions is local dates (YYYY-MM-DD) without meeting.
+	Exceptions []string  `gorm:"type:text;serializer:json" json:"exceptions"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Location return time zone of schedule.
+func (s ClassMeetingSchedule) Location() (*time.Location, error) {
+	return time.LoadLocation(s.TimeZone)
+}
+
+// Duration return duration of every meeting.
+func (s ClassMeetingSchedule) Duration() time.Duration {
+	return time.Duration(s.DurationMinutes) * time.Minute
+}
+
+// Occurrences return start of every meeting of schedule in its time zone.
+func (s ClassMeetingSchedule) Occurrences() ([]time.Time, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return nil, err
+	}
+	rule, err := recurrence.Parse(s.Rule)
+	if err != nil {
+		return nil, err
+	}
+	return rule.Occurrences(s.StartsAt.In(loc), s.Exceptions), nil
+}
+
+// ScheduleChange is change of a meeting and the following meetings of
+// schedule, nil field is not changed.
+type ScheduleChange struct {
+	Title           *string
+	Content         *string
+	Hour, Minute    *int
+	DurationMinutes *int
+}
+
+// ClassMeetingScheduleModel struct to class meeting schedule model.
+type ClassMeetingScheduleModel struct {
+	db *gorm.DB
+}
+
+// NewClassMeetingScheduleModel is function to run class meeting schedule model.
+func NewClassMeetingScheduleModel(db *gorm.DB) *ClassMeetingScheduleModel {
+	return &ClassMeetingScheduleModel{
+		db: db,
+	}
+}
+
+// GetClassSchedules is function to get schedules of class.
+func (c *ClassMeetingScheduleModel) GetClassSchedules(classID int) ([]ClassMeetingSchedule, error) {
+	var schedules []ClassMeetingSchedule
+	err := c.db.Where("class_id = ?", classID).Order("starts_at").Find(&schedules).Error
+	return schedules, err
+}
+
+// GetSchedule is function to get schedule of class by given id.
+func (c *ClassMeetingScheduleModel) GetSchedule(classID, id int) (ClassMeetingSchedule, error) {
+	var schedule ClassMeetingSchedule
+	err := c.db.Where("class_id = ? AND id = ?", classID, id).First(&schedule).Error
+	return schedule, err
+}
+
+// CreateSchedule is function to create schedule and its meetings, meeting is
+// titled with title of schedule and its number, e.g. "Pertemuan 1".
+func (c *ClassMeetingScheduleModel) CreateSchedule(schedule *ClassMeetingSchedule) ([]ClassMeeting, error) {
+	occurrences, err := schedule.Occurrences()
+	if err != nil {
+		return nil, err
+	}
+
+	var meetings []ClassMeeting
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(schedule).Error; err != nil {
+			return err
+		}
+
+		meetingModel := NewClassMeetingModel(tx)
+		for i, occurrence := range occurrences {
+			title := fmt.Sprintf("%s %d", schedule.Title, i+1)
+			meetingSlug, err := meetingModel.UniqueSlug(schedule.ClassID, slug.MakeLang(title, "id"), 0)
+			if err != nil {
+				return err
+			}
+
+			start := occurrence.UTC()
+			meeting := ClassMeeting{
+				ClassID:      schedule.ClassID,
+				Title:        title,
+				Slug:         meetingSlug,
+				Content:      schedule.Content,
+				OpenedAt:     start,
+				ClosedAt:     start.Add(schedule.Duration()),
+				ScheduleID:   &schedule.ID,
+				OccurrenceAt: &start,
+			}
+			if err := meetingModel.CreateMeeting(&meeting); err != nil {
+				return err
+			}
+			meetings = append(meetings, meeting)
+		}
+		return nil
+	})
+	return meetings, err
+}
+
+// UpdateFollowing is function to change meeting and the following meetings
+// of its schedule. The schedule is split in two at the meeting, so the
+// previous meetings keep the old schedule. It return the schedule of the
+// changed meetings.
+func (c *ClassMeetingScheduleModel) UpdateFollowing(schedule ClassMeetingSchedule, meeting ClassMeeting, change ScheduleChange) (ClassMeetingSchedule, error) {
+	if meeting.OccurrenceAt == nil {
+		return schedule, fmt.Errorf("meeting %s is not created by schedule", meeting.Slug)
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return schedule, err
+	}
+	rule, err := recurrence.Parse(schedule.Rule)
+	if err != nil {
+		return schedule, err
+	}
+
+	from := meeting.OccurrenceAt.In(loc)
+	// number of occurrences before this meeting, exceptions are counted.
+	before := 0
+	for _, occurrence := range rule.Occurrences(schedule.StartsAt.In(loc), nil) {
+		if occurrence.Before(from) {
+			before++
+		}
+	}
+
+	target := schedule
+	if before > 0 {
+		target.ID = 0
+		target.CreatedAt, target.UpdatedAt = time.Time{}, time.Time{}
+		target.StartsAt = from
+		oldRule := rule
+		if rule.Count > 0 {
+			rule.Count -= before
+			oldRule.Count = before
+		} else {
+			oldRule.Until = from.Add(-time.Second)
+		}
+		target.Rule = rule.String()
+		schedule.Rule = oldRule.String()
+	}
+
+	oldTitle := target.Title
+	if change.Title != nil {
+		target.Title = *change.Title
+	}
+	if change.Content != nil {
+		target.Content = *change.Content
+	}
+	if change.DurationMinutes != nil {
+		target.DurationMinutes = *change.DurationMinutes
+	}
+	retime := change.Hour != nil || change.DurationMinutes != nil
+	if change.Hour != nil {
+		start := target.StartsAt.In(loc)
+		target.StartsAt = time.Date(start.Year(), start.Month(), start.Day(), *change.Hour, *change.Minute, 0, 0, loc)
+	}
+	target.StartsAt = target.StartsAt.UTC()
+
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		if before > 0 {
+			if err := tx.Model(&schedule).Update("rule", schedule.Rule).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&target).Error; err != nil {
+			return err
+		}
+
+		var meetings []ClassMeeting
+		err := tx.Where("schedule_id = ? AND occurrence_at >= ?", schedule.ID, meeting.OccurrenceAt).
+			Find(&meetings).Error
+		if err != nil {
+			return err
+		}
+
+		meetingModel := NewClassMeetingModel(tx)
+		start := target.StartsAt.In(loc)
+		for _, m := range meetings {
+			m.ScheduleID = &target.ID
+			if change.Title != nil && strings.HasPrefix(m.Title, oldTitle+" ") {
+				m.Title = target.Title + strings.TrimPrefix(m.Title, oldTitle)
+				m.Slug, err = meetingModel.UniqueSlug(schedule.ClassID, slug.MakeLang(m.Title, "id"), m.ID)
+				if err != nil {
+					return err
+				}
+			}
+			if change.Content != nil {
+				m.Content = target.Content
+			}
+			if retime {
+				date := m.OccurrenceAt.In(loc)
+				opened := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, loc).UTC()
+				m.OpenedAt = opened
+				m.ClosedAt = opened.Add(target.Duration())
+				m.OccurrenceAt = &opened
+			}
+			if err := tx.Save(&m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return target, err
+}
+
+// AddException is function to skip the local date of schedule, e.g. when a
+// meeting of schedule is deleted.
+func (c *ClassMeetingScheduleModel) AddException(schedule *ClassMeetingSchedule, date string) error {
+	if slices.Contains(schedule.Exceptions, date) {
+		return nil
+	}
+	schedule.Exceptions = append(schedule.Exceptions, date)
+	return c.db.Model(schedule).Select("Exceptions").Updates(schedule).Error
+}
+
+// DeleteSchedule is function to delete schedule and its meetings that is not
+// started yet, the past meetings are kept without schedule.
+func (c *ClassMeetingScheduleModel) DeleteSchedule(schedule ClassMeetingSchedule) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("schedule_id = ? AND opened_at > ?", schedule.ID, time.Now()).
+			Delete(&ClassMeeting{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&ClassMeeting{}).Where("schedule_id = ?", schedule.ID).
+			Updates(map[string]any{"schedule_id": nil, "occurrence_at": nil}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&schedule).Error
+	})
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateFollowingTitle(t *testing.T) {
+	db := openTestDB(t)
+	class := Class{Title: "Golang Dasar", Slug: "golang-dasar"}
+	if err := NewClassModel(db).CreateNewClass(&class); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduleModel := NewClassMeetingScheduleModel(db)
+	schedule := ClassMeetingSchedule{
+		ClassID:         class.ID,
+		Title:           "Pertemuan",
+		TimeZone:        "UTC",
+		StartsAt:        time.Date(2026, time.October, 7, 12, 0, 0, 0, time.UTC),
+		DurationMinutes: 90,
+		Rule:            "FREQ=WEEKLY;COUNT=3",
+	}
+	meetings, err := scheduleModel.CreateSchedule(&schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	title := "Praktikum"
+	if _, err := scheduleModel.UpdateFollowing(schedule, meetings[1], ScheduleChange{Title: &title}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewClassMeetingModel(db).GetClassMeetings(class.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ title, slug string }{
+		{"Pertemuan 1", "pertemuan-1"},
+		{"Praktikum 2", "praktikum-2"},
+		{"Praktikum 3", "praktikum-3"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d meetings, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Title != w.title || got[i].Slug != w.slug {
+			t.Errorf("meeting %d = %q %q, want %q %q", i, got[i].Title, got[i].Slug, w.title, w.slug)
+		}
+	}
+}
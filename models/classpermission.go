@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ClassPermissionType string
 
@@ -9,11 +13,103 @@ const (
 	OUT  ClassPermissionType = "OUT"
 )
 
+// ClassPermissionStatus is review status of join or leave request.
+type ClassPermissionStatus string
+
+const (
+	PENDING  ClassPermissionStatus = "PENDING"
+	APPROVED ClassPermissionStatus = "APPROVED"
+	REJECTED ClassPermissionStatus = "REJECTED"
+)
+
+// ClassPermission is request of user to join or leave a class, it's reviewed
+// by mentor of the class.
 type ClassPermission struct {
-	ID        int `gorm:"primaryKey"`
-	UserID    int
-	ClassID   int
-	Type      ClassPermissionType `gorm:"size:4"`
-	UpdatedAt time.Time
-	CreatedAt time.Time
+	ID         int                   `gorm:"primaryKey" json:"id"`
+	UserID     int                   `gorm:"index" json:"user_id"`
+	User       *User                 `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ClassID    int                   `gorm:"index" json:"class_id"`
+	Type       ClassPermissionType   `gorm:"size:4" json:"type"`
+	Status     ClassPermissionStatus `gorm:"size:10;index" json:"status"`
+	Message    string                `gorm:"size:255" json:"message"`
+	Note       string                `gorm:"size:255" json:"note"`
+	ReviewedBy *int                  `json:"reviewed_by"`
+	ReviewedAt *time.Time            `json:"reviewed_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+// ClassPermissionModel struct to class permission model.
+type ClassPermissionModel struct {
+	db *gorm.DB
+}
+
+// NewClassPermissionModel is function to run class permission model.
+func NewClassPermissionModel(db *gorm.DB) *ClassPermissionModel {
+	return &ClassPermissionModel{
+		db: db,
+	}
+}
+
+// CreateRequest is function to create pending join or leave request.
+func (c *ClassPermissionModel) CreateRequest(request *ClassPermission) error {
+	request.Status = PENDING
+	return c.db.Create(request).Error
+}
+
+// HasPending is function to check if user has pending request of the type.
+func (c *ClassPermissionModel) HasPending(classID, userID int, requestType ClassPermissionType) (bool, error) {
+	var count int64
+	err := c.db.Model(&ClassPermission{}).
+		Where("class_id = ? AND user_id = ? AND type = ? AND status = ?", classID, userID, requestType, PENDING).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetClassRequests is function to get requests of class, all status when
+// status is empty.
+func (c *ClassPermissionModel) GetClassRequests(classID int, status ClassPermissionStatus) ([]ClassPermission, error) {
+	var requests []ClassPermission
+	query := c.db.Where("class_id = ?", classID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Preload("User").Order("created_at").Find(&requests).Error
+	return requests, err
+}
+
+// GetUserRequests is function to get requests that is submitted by user.
+func (c *ClassPermissionModel) GetUserRequests(userID int) ([]ClassPermission, error) {
+	var requests []ClassPermission
+	err := c.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&requests).Error
+	return requests, err
+}
+
+// GetRequest is function to get request of class by given id.
+func (c *ClassPermissionModel) GetRequest(classID, id int) (ClassPermission, error) {
+	var request ClassPermission
+	err := c.db.Where("class_id = ? AND id = ?", classID, id).Preload("User").First(&request).Error
+	return request, err
+}
+
+// Review is function to approve or reject request, only pending request is
+// updated. It return false when the request is already reviewed.
+func (c *ClassPermissionModel) Review(request *ClassPermission, reviewerID int, status ClassPermissionStatus, note string) (bool, error) {
+	now := time.Now()
+	result := c.db.Model(&ClassPermission{}).Where("id = ? AND status = ?", request.ID, PENDING).
+		Updates(map[string]any{
+			"status":      status,
+			"note":        note,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	request.Status = status
+	request.Note = note
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+	return true, nil
 }
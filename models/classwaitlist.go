@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClassWaitlist is user that wait for a seat in full class, the first
+// waiting user is moved to member when a seat is available.
+type ClassWaitlist struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	ClassID   int       `gorm:"uniqueIndex:idx_class_waitlist_user" json:"class_id"`
+	UserID    int       `gorm:"uniqueIndex:idx_class_waitlist_user" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClassWaitlistModel struct to class waitlist model.
+type ClassWaitlistModel struct {
+	db *gorm.DB
+}
+
+// NewClassWaitlistModel is function to run class waitlist model.
+func NewClassWaitlistModel(db *gorm.DB) *ClassWaitlistModel {
+	return &ClassWaitlistModel{
+		db: db,
+	}
+}
+
+// Add is function to add user to the end of waitlist of class.
+func (c *ClassWaitlistModel) Add(classID, userID int) error {
+	entry := ClassWaitlist{ClassID: classID, UserID: userID}
+	return c.db.Where(entry).FirstOrCreate(&entry).Error
+}
+
+// Remove is function to remove user from waitlist of class.
+func (c *ClassWaitlistModel) Remove(classID, userID int) (bool, error) {
+	result := c.db.Where("class_id = ? AND user_id = ?", classID, userID).Delete(&ClassWaitlist{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Next is function to get the first waiting user of class.
+func (c *ClassWaitlistModel) Next(classID int) (ClassWaitlist, error) {
+	var entry ClassWaitlist
+	err := c.db.Where("class_id = ?", classID).Preload("User").Order("id").First(&entry).Error
+	return entry, err
+}
+
+// Position is function to get position of user in waitlist of class, it's
+// 0 when user is not in the waitlist.
+func (c *ClassWaitlistModel) Position(classID, userID int) (int, error) {
+	var entry ClassWaitlist
+	err := c.db.Where("class_id = ? AND user_id = ?", classID, userID).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = c.db.Model(&ClassWaitlist{}).Where("class_id = ? AND id <= ?", classID, entry.ID).Count(&count).Error
+	return int(count), err
+}
+
+// GetClassWaitlist is function to get waiting users of class in order.
+func (c *ClassWaitlistModel) GetClassWaitlist(classID int) ([]ClassWaitlist, error) {
+	var entries []ClassWaitlist
+	err := c.db.Where("class_id = ?", classID).Preload("User").Order("id").Find(&entries).Error
+	return entries, err
+}
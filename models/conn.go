@@ -1,18 +1,70 @@
 package models
 
 import (
-	"gorm.io/driver/mysql"
+	"context"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/config"
 	"gorm.io/gorm"
 )
 
-func DB() *gorm.DB {
-	dsn := "root:root@tcp(127.0.0.1:3306)/kafekoding?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// Open is function to open database connection pool, it's called once at
+// startup and the result is shared to all handlers. Schema is managed by
+// the migrations package.
+func Open(cfg config.Database) (*gorm.DB, error) {
+	dialector, err := dialector(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	// time is saved in UTC, local time is computed with the time zone of
+	// the data, e.g. schedule of meeting.
+	db, err := gorm.Open(dialector, &gorm.Config{
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if cfg.Driver == config.SQLITE {
+		// sqlite only allow one writer, and each connection to
+		// ":memory:" has its own database, so keep one connection and
+		// never close it.
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime.Duration)
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime.Duration)
 	}
 
-	db.AutoMigrate(&User{}, &Class{}, &ClassMeeting{}, &ClassImage{},
-		&ClassMeetingAttendance{}, &Article{}, &ArticleComment{})
-	return db
+	ctx := context.Background()
+	if cfg.QueryTimeout.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.QueryTimeout.Duration)
+		defer cancel()
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close is function to close database connection pool.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
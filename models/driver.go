@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Driver is function to create gorm dialector from data source name.
+type Driver func(dsn string) gorm.Dialector
+
+var drivers = map[string]Driver{
+	"mysql":    mysql.Open,
+	"postgres": postgres.Open,
+	"sqlite":   sqlite.Open,
+}
+
+// RegisterDriver is function to register new database driver by name.
+func RegisterDriver(name string, driver Driver) {
+	drivers[name] = driver
+}
+
+// Drivers return name of all registered database driver.
+func Drivers() []string {
+	var names []string
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func dialector(name, dsn string) (gorm.Dialector, error) {
+	driver, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("database driver %q is not supported, use one of %v", name, Drivers())
+	}
+	return driver(dsn), nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification is message for user, e.g. result of class join request.
+type Notification struct {
+	ID        int        `gorm:"primaryKey" json:"id"`
+	UserID    int        `gorm:"index" json:"user_id"`
+	Title     string     `gorm:"size:100" json:"title"`
+	Message   string     `gorm:"type:text" json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationModel struct to notification model.
+type NotificationModel struct {
+	db *gorm.DB
+}
+
+// NewNotificationModel is function to run notification model.
+func NewNotificationModel(db *gorm.DB) *NotificationModel {
+	return &NotificationModel{
+		db: db,
+	}
+}
+
+// CreateNotifications is function to create notifications.
+func (n *NotificationModel) CreateNotifications(notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return n.db.Create(&notifications).Error
+}
+
+// GetUserNotifications is function to get newest notifications of user.
+func (n *NotificationModel) GetUserNotifications(userID int, unread bool, limit int) ([]Notification, error) {
+	var notifications []Notification
+	query := n.db.Where("user_id = ?", userID)
+	if unread {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkRead is function to mark notification of user as read, all unread
+// notification when id is 0.
+func (n *NotificationModel) MarkRead(userID, id int) error {
+	query := n.db.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if id != 0 {
+		query = query.Where("id = ?", id)
+	}
+	return query.Update("read_at", time.Now()).Error
+}
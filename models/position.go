@@ -0,0 +1,28 @@
+package models
+
+import "gorm.io/gorm"
+
+// nextPosition is function to get position after the last row of model in
+// scope, scope is column of the parent, e.g. class_id, and id is its value.
+// It must be called in the transaction that insert the row.
+func nextPosition(tx *gorm.DB, model any, scope string, id int) (int, error) {
+	var position int
+	err := tx.Model(model).Where(scope+" = ?", id).
+		Select("COALESCE(MAX(position), 0)").Scan(&position).Error
+	return position + 1, err
+}
+
+// reorder is function to set position of rows of model in scope by the order
+// of given ids.
+func reorder(tx *gorm.DB, model any, scope string, id int, ids []int) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		for i, rowID := range ids {
+			err := tx.Model(model).Where(scope+" = ? AND id = ?", id, rowID).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token is already used, all token of this login is revoked")
+)
+
+// RefreshToken is long lived token to get new access token. Token is
+// rotated on every use, tokens from one login share the same family and
+// reuse of a rotated token revoke the whole family.
+type RefreshToken struct {
+	ID           int        `gorm:"primaryKey" json:"id"`
+	FamilyID     string     `gorm:"size:36;index" json:"family_id"`
+	UserID       int        `gorm:"index" json:"user_id"`
+	TokenHash    string     `gorm:"size:64;uniqueIndex" json:"-"`
+	TokenVersion int        `json:"-"`
+	ExpiresAt    time.Time  `gorm:"index" json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// RefreshTokenModel struct to refresh token model.
+type RefreshTokenModel struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenModel is function to run refresh token model.
+func NewRefreshTokenModel(db *gorm.DB) *RefreshTokenModel {
+	return &RefreshTokenModel{
+		db: db,
+	}
+}
+
+// Issue is function to create refresh token for user, new family is created
+// when familyID is empty. It return the plain token.
+func (r *RefreshTokenModel) Issue(user User, familyID string, ttl time.Duration) (string, RefreshToken, error) {
+	token, err := auth.RandomToken()
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+
+	if familyID == "" {
+		familyID = uuid.NewString()
+	}
+
+	refreshToken := RefreshToken{
+		FamilyID:     familyID,
+		UserID:       user.ID,
+		TokenHash:    auth.HashToken(token),
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    time.Now().Add(ttl),
+	}
+	err = r.db.Create(&refreshToken).Error
+	return token, refreshToken, err
+}
+
+// Use is function to mark refresh token as used before it's rotated. When
+// the token is already used, the family and its session are revoked and
+// ErrRefreshTokenReused is returned.
+func (r *RefreshTokenModel) Use(token string) (RefreshToken, error) {
+	var refreshToken RefreshToken
+	err := r.db.Where("token_hash = ?", auth.HashToken(token)).First(&refreshToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return refreshToken, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return refreshToken, err
+	}
+
+	now := time.Now()
+	if refreshToken.RevokedAt != nil || now.After(refreshToken.ExpiresAt) {
+		return refreshToken, ErrRefreshTokenInvalid
+	}
+	if refreshToken.UsedAt != nil {
+		return refreshToken, r.reused(refreshToken.FamilyID)
+	}
+
+	// guard with used_at, concurrent use of the same token is a reuse.
+	result := r.db.Model(&RefreshToken{}).Where("id = ? AND used_at IS NULL", refreshToken.ID).Update("used_at", now)
+	if result.Error != nil {
+		return refreshToken, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return refreshToken, r.reused(refreshToken.FamilyID)
+	}
+
+	refreshToken.UsedAt = &now
+	return refreshToken, nil
+}
+
+// reused revoke the session of the family, so its access token is rejected
+// too, with all refresh token in the family.
+func (r *RefreshTokenModel) reused(familyID string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return NewSessionModel(tx).RevokeSession(familyID)
+	})
+	if err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// RevokeFamily is function to revoke all refresh token in a family.
+func (r *RefreshTokenModel) RevokeFamily(familyID string) error {
+	return r.db.Model(&RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUser is function to revoke all refresh token of user.
+func (r *RefreshTokenModel) RevokeUser(userID int) error {
+	return r.db.Model(&RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// GetActive is function to get refresh token that is not used, revoked or
+// expired by plain token.
+func (r *RefreshTokenModel) GetActive(token string) (RefreshToken, error) {
+	var refreshToken RefreshToken
+	err := r.db.Where("token_hash = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		auth.HashToken(token), time.Now()).First(&refreshToken).Error
+	return refreshToken, err
+}
+
+// DeleteExpired is function to delete refresh token that expired before
+// given time.
+func (r *RefreshTokenModel) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&RefreshToken{})
+	return result.RowsAffected, result.Error
+}
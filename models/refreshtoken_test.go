@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenUse(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUsers(t, db, "member")[0]
+	refreshTokens := NewRefreshTokenModel(db)
+
+	valid, _, err := refreshTokens.Issue(user, "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _, err := refreshTokens.Issue(user, "", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, issued, err := refreshTokens.Issue(user, "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := refreshTokens.RevokeFamily(issued.FamilyID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"valid", valid, nil},
+		{"unknown", "unknown-token", ErrRefreshTokenInvalid},
+		{"expired", expired, ErrRefreshTokenInvalid},
+		{"revoked", revoked, ErrRefreshTokenInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshToken, err := refreshTokens.Use(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Use() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && refreshToken.UsedAt == nil {
+				t.Error("used token has no used_at")
+			}
+		})
+	}
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUsers(t, db, "member")[0]
+	sessions := NewSessionModel(db)
+	refreshTokens := NewRefreshTokenModel(db)
+
+	session, err := sessions.CreateSession(user.ID, "test", "127.0.0.1", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := sessions.CreateSession(user.ID, "test", "127.0.0.1", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, _, err := refreshTokens.Issue(user, other.ID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// login then refresh, the first token is rotated to the second one.
+	first, _, err := refreshTokens.Issue(user, session.ID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := refreshTokens.Use(first); err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := refreshTokens.Issue(user, session.ID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := refreshTokens.Use(first); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reuse error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		active bool
+	}{
+		{"rotated token of reused family", second, false},
+		{"token of other session", otherToken, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := refreshTokens.GetActive(tt.token)
+			if active := err == nil; active != tt.active {
+				t.Errorf("active = %v, want %v", active, tt.active)
+			}
+		})
+	}
+
+	if _, err := sessions.GetActiveSession(session.ID); err == nil {
+		t.Error("session of reused family is still active")
+	}
+	if _, err := sessions.GetActiveSession(other.ID); err != nil {
+		t.Errorf("other session is revoked: %v", err)
+	}
+	// reuse again after the family is revoked is rejected as invalid.
+	if _, err := refreshTokens.Use(first); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("use after revoke error = %v, want %v", err, ErrRefreshTokenInvalid)
+	}
+}
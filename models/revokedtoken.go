@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedToken is access token that is revoked before it's expired, it's
+// deleted after the token is expired.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:36" json:"jti"`
+	UserID    int       `gorm:"index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RevokedTokenModel struct to revoked token model.
+type RevokedTokenModel struct {
+	db *gorm.DB
+}
+
+// NewRevokedTokenModel is function to run revoked token model.
+func NewRevokedTokenModel(db *gorm.DB) *RevokedTokenModel {
+	return &RevokedTokenModel{
+		db: db,
+	}
+}
+
+// Revoke is function to add access token to revocation list.
+func (r *RevokedTokenModel) Revoke(jti string, userID int, expiresAt time.Time) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+// IsRevoked is function to check if access token is in revocation list.
+func (r *RevokedTokenModel) IsRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired is function to delete revoked token that expired before
+// given time, expired token is rejected anyway.
+func (r *RevokedTokenModel) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&RevokedToken{})
+	return result.RowsAffected, result.Error
+}
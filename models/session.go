@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is one login of user, refresh token of the login use the session
+// id as family id and access token carry the session id.
+type Session struct {
+	ID         string     `gorm:"primaryKey;size:36" json:"id"`
+	UserID     int        `gorm:"index" json:"user_id"`
+	UserAgent  string     `gorm:"size:255" json:"user_agent"`
+	IP         string     `gorm:"size:45" json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"index" json:"expires_at"`
+	RevokedAt  *time.Time `json:"-"`
+}
+
+// SessionModel struct to session model.
+type SessionModel struct {
+	db *gorm.DB
+}
+
+// NewSessionModel is function to run session model.
+func NewSessionModel(db *gorm.DB) *SessionModel {
+	return &SessionModel{
+		db: db,
+	}
+}
+
+// CreateSession is function to create new session for user.
+func (s *SessionModel) CreateSession(userID int, userAgent, ip string, ttl time.Duration) (Session, error) {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	now := time.Now()
+	session := Session{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	err := s.db.Create(&session).Error
+	return session, err
+}
+
+// GetActiveSession is function to get session that is not revoked or expired.
+func (s *SessionModel) GetActiveSession(id string) (Session, error) {
+	var session Session
+	err := s.db.Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).First(&session).Error
+	return session, err
+}
+
+// GetActiveSessions is function to get all active session of user.
+func (s *SessionModel) GetActiveSessions(userID int) ([]Session, error) {
+	var sessions []Session
+	err := s.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// CountActive is function to count active session of user.
+func (s *SessionModel) CountActive(userID int) (int64, error) {
+	var count int64
+	err := s.db.Model(&Session{}).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Count(&count).Error
+	return count, err
+}
+
+// Touch is function to update last seen of session, it's updated at most
+// once per minute to reduce write.
+func (s *SessionModel) Touch(session Session, userAgent, ip string) error {
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < time.Minute && session.IP == ip {
+		return nil
+	}
+
+	updates := map[string]any{"last_seen_at": now, "ip": ip}
+	if userAgent != "" && len(userAgent) <= 255 {
+		updates["user_agent"] = userAgent
+	}
+	return s.db.Model(&Session{}).Where("id = ?", session.ID).Updates(updates).Error
+}
+
+// Extend is function to set new expiration time of session.
+func (s *SessionModel) Extend(id string, expiresAt time.Time) error {
+	return s.db.Model(&Session{}).Where("id = ?", id).Update("expires_at", expiresAt).Error
+}
+
+// RevokeSession is function to revoke session and its refresh token.
+func (s *SessionModel) RevokeSession(id string) error {
+	err := s.db.Model(&Session{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return err
+	}
+	return NewRefreshTokenModel(s.db).RevokeFamily(id)
+}
+
+// RevokeUser is function to revoke all session of user.
+func (s *SessionModel) RevokeUser(userID int) error {
+	return s.db.Model(&Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// DeleteExpired is function to delete session that expired before given
+// time or revoked, access token of deleted session is rejected.
+func (s *SessionModel) DeleteExpired(before time.Time) (int64, error) {
+	result := s.db.Where("expires_at < ? OR revoked_at IS NOT NULL", before).Delete(&Session{})
+	return result.RowsAffected, result.Error
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Aeroxee/kafekoding-api/auth"
+	"gorm.io/gorm"
 )
 
 type UserType int8
@@ -11,8 +14,35 @@ type UserType int8
 const (
 	ADMIN UserType = iota
 	MEMBER
+	MENTOR
+	EDITOR
 )
 
+var userTypeNames = map[UserType]string{
+	ADMIN:  "ADMIN",
+	MEMBER: "MEMBER",
+	MENTOR: "MENTOR",
+	EDITOR: "EDITOR",
+}
+
+// String return name of user type.
+func (t UserType) String() string {
+	if name, ok := userTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("UserType(%d)", t)
+}
+
+// ParseUserType is function to get user type by name, e.g. "admin".
+func ParseUserType(name string) (UserType, error) {
+	for t, n := range userTypeNames {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("user type %q is not valid", name)
+}
+
 type User struct {
 	ID           int       `gorm:"primaryKey" json:"id,omitempty"`
 	FirstName    string    `gorm:"size:50" json:"first_name,omitempty"`
@@ -24,6 +54,7 @@ type User struct {
 	IsActive     bool      `gorm:"default:false" json:"is_active,omitempty"`
 	IsLogined    bool      `gorm:"default:false" json:"is_logined,omitempty"`
 	Type         UserType  `gorm:"default:1" json:"type"`
+	TokenVersion int       `gorm:"default:0" json:"-"`
 	UpdatedAt    time.Time `json:"updated_at,omitempty"`
 	DateJoined   time.Time `gorm:"autoCreateTime" json:"date_joined,omitempty"`
 	Articles     []Article `gorm:"foreignKey:UserID" json:"articles,omitempty"`
@@ -31,28 +62,65 @@ type User struct {
 	ClassMembers []*Class  `gorm:"many2many:classes_user_member" json:"class_members,omitempty"`
 }
 
-func CreateNewUser(user *User) error {
+// UserModel struct to user model.
+type UserModel struct {
+	db *gorm.DB
+}
+
+// NewUserModel is function to run user model.
+func NewUserModel(db *gorm.DB) *UserModel {
+	return &UserModel{
+		db: db,
+	}
+}
+
+// CreateNewUser is function to create new user with hashed password.
+func (u *UserModel) CreateNewUser(user *User) error {
 	user.Password = auth.EncryptionPassword(user.Password)
-	return DB().Create(user).Error
+	return u.db.Create(user).Error
 }
 
-func GetUserByID(id int) (User, error) {
+// RevokeTokens is function to revoke all issued access and refresh token
+// and session of user by increasing the token version.
+func (u *UserModel) RevokeTokens(userID int) error {
+	err := u.db.Model(&User{}).Where("id = ?", userID).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+	if err != nil {
+		return err
+	}
+	if err := NewSessionModel(u.db).RevokeUser(userID); err != nil {
+		return err
+	}
+	return NewRefreshTokenModel(u.db).RevokeUser(userID)
+}
+
+// GetUserByID is function to get user by given id.
+func (u *UserModel) GetUserByID(id int) (User, error) {
 	var user User
-	err := DB().Model(&User{}).Where("id = ?", id).Preload("Articles").Preload("ClassMentors").
+	err := u.db.Model(&User{}).Where("id = ?", id).Preload("Articles").Preload("ClassMentors").
 		Preload("ClassMembers").First(&user).Error
 	return user, err
 }
 
-func GetUserByUsername(username string) (User, error) {
+// GetUserByUsername is function to get user by given username.
+func (u *UserModel) GetUserByUsername(username string) (User, error) {
 	var user User
-	err := DB().Model(&User{}).Where("username = ?", username).Preload("Articles").Preload("ClassMentors").
+	err := u.db.Model(&User{}).Where("username = ?", username).Preload("Articles").Preload("ClassMentors").
 		Preload("ClassMembers").First(&user).Error
 	return user, err
 }
 
-func GetUserByEmail(email string) (User, error) {
+// GetUserByEmail is function to get user by given email.
+func (u *UserModel) GetUserByEmail(email string) (User, error) {
 	var user User
-	err := DB().Model(&User{}).Where("email = ?", email).Preload("Articles").Preload("ClassMentors").
+	err := u.db.Model(&User{}).Where("email = ?", email).Preload("Articles").Preload("ClassMentors").
 		Preload("ClassMembers").First(&user).Error
 	return user, err
 }
+
+// GetUsersByType is function to get active users of given type, e.g. all ADMIN.
+func (u *UserModel) GetUsersByType(userType UserType) ([]User, error) {
+	var users []User
+	err := u.db.Where("type = ? AND is_active = ?", userType, true).Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Aeroxee/kafekoding-api/auth"
+	"gorm.io/gorm"
+)
+
+type TokenPurpose string
+
+const (
+	ACTIVATION     TokenPurpose = "ACTIVATION"
+	PASSWORD_RESET TokenPurpose = "PASSWORD_RESET"
+	// CALENDAR token is used many times in link of calendar feed.
+	CALENDAR TokenPurpose = "CALENDAR"
+)
+
+// UserToken is one time token sent to user by email, only hash of the
+// token is saved.
+type UserToken struct {
+	ID        int          `gorm:"primaryKey" json:"id"`
+	UserID    int          `gorm:"index" json:"user_id"`
+	Purpose   TokenPurpose `gorm:"size:20;index" json:"purpose"`
+	TokenHash string       `gorm:"size:64;uniqueIndex" json:"-"`
+	ExpiresAt time.Time    `gorm:"index" json:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// UserTokenModel struct to user token model.
+type UserTokenModel struct {
+	db *gorm.DB
+}
+
+// NewUserTokenModel is function to run user token model.
+func NewUserTokenModel(db *gorm.DB) *UserTokenModel {
+	return &UserTokenModel{
+		db: db,
+	}
+}
+
+// CreateToken is function to create new token for user, previous unused
+// token with the same purpose is expired. It return the plain token.
+func (u *UserTokenModel) CreateToken(userID int, purpose TokenPurpose, ttl time.Duration) (string, error) {
+	token, err := auth.RandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = u.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", userID, purpose, now).
+			Update("expires_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&UserToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: auth.HashToken(token),
+			ExpiresAt: now.Add(ttl),
+		}).Error
+	})
+	return token, err
+}
+
+// UseToken is function to mark valid token as used, the token can not be
+// used again.
+func (u *UserTokenModel) UseToken(purpose TokenPurpose, token string) (UserToken, error) {
+	var userToken UserToken
+	now := time.Now()
+	err := u.db.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?",
+		auth.HashToken(token), purpose, now).First(&userToken).Error
+	if err != nil {
+		return userToken, err
+	}
+
+	// guard with used_at so the same token can not be used twice concurrently.
+	result := u.db.Model(&UserToken{}).Where("id = ? AND used_at IS NULL", userToken.ID).Update("used_at", now)
+	if result.Error != nil {
+		return userToken, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return userToken, gorm.ErrRecordNotFound
+	}
+
+	userToken.UsedAt = &now
+	return userToken, nil
+}
+
+// FindToken is function to get valid token without mark it as used.
+func (u *UserTokenModel) FindToken(purpose TokenPurpose, token string) (UserToken, error) {
+	var userToken UserToken
+	err := u.db.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?",
+		auth.HashToken(token), purpose, time.Now()).First(&userToken).Error
+	return userToken, err
+}
+
+// ExpireTokens is function to expire all valid token of user with the
+// given purpose.
+func (u *UserTokenModel) ExpireTokens(userID int, purpose TokenPurpose) error {
+	now := time.Now()
+	return u.db.Model(&UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", userID, purpose, now).
+		Update("expires_at", now).Error
+}
+
+// LastToken is function to get last created token of user.
+func (u *UserTokenModel) LastToken(userID int, purpose TokenPurpose) (UserToken, error) {
+	var userToken UserToken
+	err := u.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("created_at DESC").
+		First(&userToken).Error
+	return userToken, err
+}
+
+// CountSince is function to count token of user created after given time.
+func (u *UserTokenModel) CountSince(userID int, purpose TokenPurpose, since time.Time) (int64, error) {
+	var count int64
+	err := u.db.Model(&UserToken{}).Where("user_id = ? AND purpose = ? AND created_at > ?", userID, purpose, since).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteExpired is function to delete token that expired before given time.
+func (u *UserTokenModel) DeleteExpired(before time.Time) (int64, error) {
+	result := u.db.Where("expires_at < ?", before).Delete(&UserToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,116 @@
+// Package policy decide what a user is allowed to do, so every handler and
+// middleware check permission with the same rules.
+package policy
+
+import (
+	"slices"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+)
+
+// Action is something that user want to do.
+type Action string
+
+const (
+	CreateClass        Action = "class.create"
+	UpdateClass        Action = "class.update"
+	DeleteClass        Action = "class.delete"
+	ManageClassMembers Action = "class.members"
+	ManageClassMentors Action = "class.mentors"
+	TransferClass      Action = "class.transfer"
+	ManageMeetings     Action = "class.meetings"
+	ManageClassImages  Action = "class.images"
+	ManageCurriculum   Action = "class.curriculum"
+	ManageAssignments  Action = "class.assignments"
+	SubmitAssignment   Action = "class.submit"
+	AttendMeeting      Action = "class.attend"
+	CreateArticle      Action = "article.create"
+	UpdateArticle      Action = "article.update"
+	DeleteArticle      Action = "article.delete"
+)
+
+// Rule decide whether user can do an action on the given resource. Resource is
+// nil when the action is not bound to a resource, e.g. CreateClass.
+type Rule func(user models.User, resource any) bool
+
+// roles is actions that a role can do on every resource. ADMIN can do
+// everything.
+var roles = map[models.UserType][]Action{
+	models.EDITOR: {CreateArticle, UpdateArticle, DeleteArticle},
+	models.MENTOR: {CreateArticle},
+	models.MEMBER: {CreateArticle},
+}
+
+// rules is per resource permission, checked when role of user doesn't
+// allow the action.
+var rules = map[Action]Rule{
+	UpdateClass:        isClassStaff,
+	ManageClassMembers: isClassStaff,
+	ManageClassMentors: isClassOwner,
+	TransferClass:      isClassOwner,
+	ManageMeetings:     isClassStaff,
+	ManageClassImages:  isClassStaff,
+	ManageCurriculum:   isClassStaff,
+	ManageAssignments:  isClassStaff,
+	SubmitAssignment:   isClassMember,
+	AttendMeeting:      isClassMember,
+	UpdateArticle:      isArticleOwner,
+	DeleteArticle:      isArticleOwner,
+}
+
+// Can is function to check if user can do action on resource.
+func Can(user models.User, action Action, resource any) bool {
+	if user.Type == models.ADMIN {
+		return true
+	}
+	if slices.Contains(roles[user.Type], action) {
+		return true
+	}
+	rule, ok := rules[action]
+	return ok && rule(user, resource)
+}
+
+func classOf(resource any) *models.Class {
+	switch r := resource.(type) {
+	case models.Class:
+		return &r
+	case *models.Class:
+		return r
+	}
+	return nil
+}
+
+// user is the owner mentor or a co-mentor of the class. Class without mentor
+// can be managed by ADMIN only.
+func isClassStaff(user models.User, resource any) bool {
+	class := classOf(resource)
+	return class != nil && class.IsMentor(user.ID)
+}
+
+// user is member of the class.
+func isClassMember(user models.User, resource any) bool {
+	class := classOf(resource)
+	return class != nil && class.IsMember(user.ID)
+}
+
+// user is the owner mentor of the class.
+func isClassOwner(user models.User, resource any) bool {
+	class := classOf(resource)
+	return class != nil && class.IsOwner(user.ID)
+}
+
+// user is the author of article.
+func isArticleOwner(user models.User, resource any) bool {
+	switch r := resource.(type) {
+	case models.Article:
+		return r.UserID == user.ID
+	case *models.Article:
+		return r != nil && r.UserID == user.ID
+	}
+	return false
+}
+
+// CanMentor is function to check if user can be added as mentor of a class.
+func CanMentor(user models.User) bool {
+	return user.Type == models.MENTOR || user.Type == models.ADMIN
+}
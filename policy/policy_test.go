@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/Aeroxee/kafekoding-api/models"
+)
+
+func TestCanClass(t *testing.T) {
+	owner := models.User{ID: 1, Type: models.MENTOR}
+	mentor := models.User{ID: 2, Type: models.MENTOR}
+	member := models.User{ID: 3, Type: models.MEMBER}
+	admin := models.User{ID: 4, Type: models.ADMIN}
+	outsider := models.User{ID: 5, Type: models.MENTOR}
+	class := models.Class{
+		OwnerID: &owner.ID,
+		Mentors: []*models.User{&owner, &mentor},
+		Members: []*models.User{&member},
+	}
+
+	type allowed struct {
+		owner, mentor, member, admin, outsider bool
+	}
+	tests := []struct {
+		action Action
+		want   allowed
+	}{
+		{UpdateClass, allowed{owner: true, mentor: true, admin: true}},
+		{DeleteClass, allowed{admin: true}},
+		{ManageClassMembers, allowed{owner: true, mentor: true, admin: true}},
+		{ManageClassMentors, allowed{owner: true, admin: true}},
+		{TransferClass, allowed{owner: true, admin: true}},
+		{ManageMeetings, allowed{owner: true, mentor: true, admin: true}},
+		{ManageClassImages, allowed{owner: true, mentor: true, admin: true}},
+		{ManageCurriculum, allowed{owner: true, mentor: true, admin: true}},
+		{ManageAssignments, allowed{owner: true, mentor: true, admin: true}},
+		{SubmitAssignment, allowed{member: true, admin: true}},
+		{AttendMeeting, allowed{member: true, admin: true}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			users := []struct {
+				name string
+				user models.User
+				want bool
+			}{
+				{"owner", owner, tt.want.owner},
+				{"mentor", mentor, tt.want.mentor},
+				{"member", member, tt.want.member},
+				{"admin", admin, tt.want.admin},
+				{"outsider", outsider, tt.want.outsider},
+			}
+			for _, u := range users {
+				if got := Can(u.user, tt.action, class); got != u.want {
+					t.Errorf("%s: Can() = %v, want %v", u.name, got, u.want)
+				}
+				if got := Can(u.user, tt.action, &class); got != u.want {
+					t.Errorf("%s: Can() with pointer = %v, want %v", u.name, got, u.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCanClassWithoutMentor(t *testing.T) {
+	class := models.Class{}
+	mentor := models.User{ID: 1, Type: models.MENTOR}
+	admin := models.User{ID: 2, Type: models.ADMIN}
+
+	if Can(mentor, UpdateClass, class) {
+		t.Error("mentor can update class without mentor")
+	}
+	if !Can(admin, UpdateClass, class) {
+		t.Error("admin can't update class without mentor")
+	}
+	if Can(mentor, UpdateClass, nil) {
+		t.Error("mentor can update nil class")
+	}
+}
+
+func TestCanCreateClass(t *testing.T) {
+	tests := []struct {
+		userType models.UserType
+		want     bool
+	}{
+		{models.ADMIN, true},
+		{models.MENTOR, false},
+		{models.MEMBER, false},
+		{models.EDITOR, false},
+	}
+	for _, tt := range tests {
+		if got := Can(models.User{Type: tt.userType}, CreateClass, nil); got != tt.want {
+			t.Errorf("%s: Can() = %v, want %v", tt.userType, got, tt.want)
+		}
+	}
+}
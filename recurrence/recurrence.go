@@ -0,0 +1,200 @@
+// Package recurrence is a subset of iCalendar RRULE (RFC 5545) to repeat
+// class meetings every week or every two weeks, e.g.
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences is maximum occurrences of a rule, it stop a rule from
+// creating too many meetings.
+const MaxOccurrences = 200
+
+// DateLayout is layout of exception date, it's the local date of occurrence.
+const DateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// Rule is weekly recurrence rule. One of Count or Until is required so the
+// occurrences are finite.
+type Rule struct {
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    time.Time
+}
+
+// Parse is function to parse RRULE value, the "RRULE:" prefix is optional.
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	var freq string
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("recurrence: invalid part %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			freq = strings.ToUpper(val)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 || interval > 2 {
+				return rule, errors.New("recurrence: INTERVAL must be 1 (weekly) or 2 (biweekly)")
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return rule, errors.New("recurrence: COUNT must be a positive number")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return rule, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, name := range strings.Split(strings.ToUpper(val), ",") {
+				day, ok := weekdays[name]
+				if !ok {
+					return rule, fmt.Errorf("recurrence: invalid BYDAY %q", name)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		default:
+			return rule, fmt.Errorf("recurrence: %s is not supported", name)
+		}
+	}
+
+	if freq != "WEEKLY" {
+		return rule, errors.New("recurrence: only FREQ=WEEKLY is supported")
+	}
+	if rule.Count == 0 && rule.Until.IsZero() {
+		return rule, errors.New("recurrence: COUNT or UNTIL is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return rule, errors.New("recurrence: COUNT and UNTIL can't be used together")
+	}
+	if rule.Count > MaxOccurrences {
+		return rule, fmt.Errorf("recurrence: COUNT is maximum %d", MaxOccurrences)
+	}
+	return rule, nil
+}
+
+// UNTIL is a UTC date time or a date, date is the end of that day in UTC.
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	until, err := time.Parse("20060102", value)
+	if err != nil {
+		return until, errors.New("recurrence: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ")
+	}
+	return until.Add(24*time.Hour - time.Second), nil
+}
+
+// String return the rule in RRULE format.
+func (r Rule) String() string {
+	parts := []string{"FREQ=WEEKLY"}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, day := range r.ByDay {
+			days = append(days, weekdayNames[day])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences is function to get start time of every occurrence from start.
+// Occurrences are computed in the location of start, so the meeting stay at
+// the same local time when daylight saving time change. Occurrence on the
+// local date in exceptions is skipped but it's still counted by COUNT.
+func (r Rule) Occurrences(start time.Time, exceptions []string) []time.Time {
+	skip := make(map[string]bool, len(exceptions))
+	for _, date := range exceptions {
+		skip[date] = true
+	}
+
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+	// weeks start on monday.
+	offsets := make([]int, 0, len(days))
+	for _, day := range days {
+		offsets = append(offsets, (int(day)+6)%7)
+	}
+	sort.Ints(offsets)
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	loc := start.Location()
+	weekStart := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	var result []time.Time
+	count := 0
+	for week := 0; count < MaxOccurrences; week += interval {
+		for _, offset := range offsets {
+			date := weekStart.AddDate(0, 0, week*7+offset)
+			occurrence := time.Date(date.Year(), date.Month(), date.Day(),
+				start.Hour(), start.Minute(), start.Second(), 0, loc)
+			if occurrence.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && occurrence.After(r.Until) {
+				return result
+			}
+
+			count++
+			if !skip[occurrence.Format(DateLayout)] {
+				result = append(result, occurrence)
+			}
+			if count == r.Count || count == MaxOccurrences {
+				return result
+			}
+		}
+	}
+	return result
+}
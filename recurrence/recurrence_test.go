@@ -0,0 +1,147 @@
+package recurrence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		err   bool
+	}{
+		{value: "FREQ=WEEKLY;COUNT=10", want: "FREQ=WEEKLY;COUNT=10"},
+		{value: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10"},
+		{value: "freq=weekly;byday=fr;until=20261231", want: "FREQ=WEEKLY;BYDAY=FR;UNTIL=20261231T235959Z"},
+		{value: "FREQ=WEEKLY;UNTIL=20261231T100000Z", want: "FREQ=WEEKLY;UNTIL=20261231T100000Z"},
+		{value: "FREQ=DAILY;COUNT=10", err: true},
+		{value: "FREQ=WEEKLY", err: true},
+		{value: "FREQ=WEEKLY;COUNT=2;UNTIL=20261231", err: true},
+		{value: "FREQ=WEEKLY;INTERVAL=3;COUNT=2", err: true},
+		{value: "FREQ=WEEKLY;COUNT=0", err: true},
+		{value: "FREQ=WEEKLY;COUNT=201", err: true},
+		{value: "FREQ=WEEKLY;BYDAY=XX;COUNT=2", err: true},
+		{value: "FREQ=WEEKLY;UNTIL=2026-12-31", err: true},
+		{value: "FREQ=WEEKLY;BYMONTH=1;COUNT=2", err: true},
+		{value: "FREQ=WEEKLY;COUNT", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("Parse() = %v, want error", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 19, 0, 0, 0, jakarta)
+	}
+	// wednesday
+	start := date(time.October, 7)
+
+	tests := []struct {
+		name       string
+		rule       string
+		exceptions []string
+		want       []time.Time
+	}{
+		{
+			name: "weekly on day of start",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			want: []time.Time{date(time.October, 7), date(time.October, 14), date(time.October, 21)},
+		},
+		{
+			name: "biweekly skip days before start",
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4",
+			want: []time.Time{date(time.October, 7), date(time.October, 19), date(time.October, 21), date(time.November, 2)},
+		},
+		{
+			name:       "exception is counted",
+			rule:       "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4",
+			exceptions: []string{"2026-10-19"},
+			want:       []time.Time{date(time.October, 7), date(time.October, 21), date(time.November, 2)},
+		},
+		{
+			name: "until end of day",
+			rule: "FREQ=WEEKLY;BYDAY=WE;UNTIL=20261021",
+			want: []time.Time{date(time.October, 7), date(time.October, 14), date(time.October, 21)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rule.Occurrences(start, tt.exceptions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrencesAcrossDaylightSavingTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		zone  string
+		start time.Time
+		// UTC hour of the first and the last occurrence.
+		firstUTC, lastUTC int
+	}{
+		// DST start on 8 March 2026 in New York, 19:00 EST is 00:00 UTC and
+		// 19:00 EDT is 23:00 UTC.
+		{"spring forward", "America/New_York", time.Date(2026, time.March, 2, 19, 0, 0, 0, time.UTC), 0, 23},
+		// DST end on 25 October 2026 in Berlin, 10:00 CEST is 08:00 UTC and
+		// 10:00 CET is 09:00 UTC.
+		{"fall back", "Europe/Berlin", time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC), 8, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Date(tt.start.Year(), tt.start.Month(), tt.start.Day(), tt.start.Hour(), 0, 0, 0, loc)
+			rule, err := Parse("FREQ=WEEKLY;COUNT=2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := rule.Occurrences(start, nil)
+			if len(got) != 2 {
+				t.Fatalf("got %d occurrences, want 2", len(got))
+			}
+			for _, occurrence := range got {
+				if occurrence.Hour() != start.Hour() || occurrence.Weekday() != start.Weekday() {
+					t.Errorf("occurrence %v is not at local %v %02d:00", occurrence, start.Weekday(), start.Hour())
+				}
+			}
+			if hour := got[0].UTC().Hour(); hour != tt.firstUTC {
+				t.Errorf("first occurrence at %02d:00 UTC, want %02d:00", hour, tt.firstUTC)
+			}
+			if hour := got[1].UTC().Hour(); hour != tt.lastUTC {
+				t.Errorf("last occurrence at %02d:00 UTC, want %02d:00", hour, tt.lastUTC)
+			}
+		})
+	}
+}